@@ -0,0 +1,136 @@
+package htmlmetadata
+
+import "strings"
+
+// PaywallStatus is the verdict of Result.Access.
+type PaywallStatus int
+
+const (
+	// PaywallUnknown means the page gives no signal either way.
+	PaywallUnknown PaywallStatus = iota
+	// Paywalled means the page marks some or all of its content as requiring
+	// a subscription or login.
+	Paywalled
+	// NotPaywalled means the page marks its content as free to read.
+	NotPaywalled
+)
+
+// String returns a lowercase name for s.
+func (s PaywallStatus) String() string {
+	switch s {
+	case Paywalled:
+		return "paywalled"
+	case NotPaywalled:
+		return "free"
+	default:
+		return "unknown"
+	}
+}
+
+// AccessInfo describes the access restrictions a page declares.
+type AccessInfo struct {
+	// IsPaywalled is Paywalled if any signal indicates restricted content,
+	// NotPaywalled if there are signals and all say the content is free, and
+	// PaywallUnknown if there are none.
+	IsPaywalled PaywallStatus `json:"is_paywalled"`
+	// Selectors lists the cssSelector of each part of the page that JSON-LD
+	// marks as not accessible for free, as publishers do for Google's
+	// subscription and paywalled content markup.
+	Selectors []string `json:"selectors,omitempty"`
+	// Signals lists the evidence found, such as
+	// "ld+json isAccessibleForFree=false" or "article:content_tier=locked".
+	Signals []string `json:"signals,omitempty"`
+}
+
+// Access looks for paywall hints in the page: the isAccessibleForFree
+// property of JSON-LD objects, including their @graph and hasPart entries,
+// and the article:content_tier meta property, whose values "locked" and
+// "metered" count as paywalled and "free" does not. This is a heuristic:
+// pages that enforce a paywall without declaring it come out as
+// PaywallUnknown.
+func (r *Result) Access() AccessInfo {
+	var info AccessInfo
+	restricted, free := false, false
+	for _, obj := range r.JSONLD {
+		walkJSONLD(obj, 0, func(obj map[string]interface{}) {
+			accessible, ok := jsonLDBool(obj["isAccessibleForFree"])
+			if !ok {
+				return
+			}
+			if accessible {
+				free = true
+				info.Signals = append(info.Signals, "ld+json isAccessibleForFree=true")
+				return
+			}
+			restricted = true
+			info.Signals = append(info.Signals, "ld+json isAccessibleForFree=false")
+			if sel, ok := obj["cssSelector"].(string); ok && sel != "" {
+				info.Selectors = append(info.Selectors, sel)
+			}
+		})
+	}
+	for _, tag := range r.Tags {
+		if !strings.EqualFold(tag.Name, "article:content_tier") {
+			continue
+		}
+		tier := strings.ToLower(strings.TrimSpace(tag.Content))
+		switch tier {
+		case "locked", "metered":
+			restricted = true
+		case "free":
+			free = true
+		default:
+			continue
+		}
+		info.Signals = append(info.Signals, "article:content_tier="+tier)
+	}
+
+	switch {
+	case restricted:
+		info.IsPaywalled = Paywalled
+	case free:
+		info.IsPaywalled = NotPaywalled
+	}
+	return info
+}
+
+// maxJSONLDDepth bounds the nesting walkJSONLD descends into.
+const maxJSONLDDepth = 8
+
+// walkJSONLD calls fn for obj and for every object in its @graph and hasPart
+// properties, recursively.
+func walkJSONLD(obj map[string]interface{}, depth int, fn func(map[string]interface{})) {
+	if depth > maxJSONLDDepth {
+		return
+	}
+	fn(obj)
+	for _, key := range []string{"@graph", "hasPart"} {
+		switch v := obj[key].(type) {
+		case map[string]interface{}:
+			walkJSONLD(v, depth+1, fn)
+		case []interface{}:
+			for _, item := range v {
+				if child, ok := item.(map[string]interface{}); ok {
+					walkJSONLD(child, depth+1, fn)
+				}
+			}
+		}
+	}
+}
+
+// jsonLDBool interprets v as a boolean, accepting the "True" and "False"
+// strings many publishers emit in place of JSON booleans.
+func jsonLDBool(v interface{}) (value, ok bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,87 @@
+package htmlmetadata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAccessNewsFixtures(t *testing.T) {
+	tests := []struct {
+		name, doc string
+		want      PaywallStatus
+		selectors []string
+		signals   []string
+	}{
+		{
+			name: "subscriber article",
+			doc: `<html><head>
+<meta property="og:type" content="article">
+<script type="application/ld+json">
+{"@context":"https://schema.org","@type":"NewsArticle","headline":"Markets rally",
+ "isAccessibleForFree":"False",
+ "hasPart":{"@type":"WebPageElement","isAccessibleForFree":"False","cssSelector":".paywall"}}
+</script>
+</head><body><p>Lede</p><div class="paywall">Rest</div></body></html>`,
+			want:      Paywalled,
+			selectors: []string{".paywall"},
+			signals:   []string{"ld+json isAccessibleForFree=false", "ld+json isAccessibleForFree=false"},
+		},
+		{
+			name: "graph with free article",
+			doc: `<html><head>
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+ {"@type":"WebSite","name":"Daily News"},
+ {"@type":"NewsArticle","headline":"Weather","isAccessibleForFree":true}]}
+</script>
+</head></html>`,
+			want:    NotPaywalled,
+			signals: []string{"ld+json isAccessibleForFree=true"},
+		},
+		{
+			name: "metered tier",
+			doc: `<html><head>
+<meta property="article:published_time" content="2024-03-01T08:00:00Z">
+<meta property="article:content_tier" content="Metered">
+</head></html>`,
+			want:    Paywalled,
+			signals: []string{"article:content_tier=metered"},
+		},
+		{
+			name:    "free tier",
+			doc:     `<html><head><meta property="article:content_tier" content="free"></head></html>`,
+			want:    NotPaywalled,
+			signals: []string{"article:content_tier=free"},
+		},
+		{
+			name: "free article with locked tier",
+			doc: `<html><head>
+<script type="application/ld+json">{"@type":"NewsArticle","isAccessibleForFree":true}</script>
+<meta property="article:content_tier" content="locked">
+</head></html>`,
+			want:    Paywalled,
+			signals: []string{"ld+json isAccessibleForFree=true", "article:content_tier=locked"},
+		},
+		{
+			name: "no signals",
+			doc: `<html><head>
+<meta property="og:type" content="article">
+<meta property="article:content_tier" content="premium">
+<script type="application/ld+json">{"@type":"NewsArticle","headline":"Untagged"}</script>
+</head></html>`,
+			want: PaywallUnknown,
+		},
+	}
+	for _, tt := range tests {
+		info := parseString(t, tt.doc).Access()
+		if info.IsPaywalled != tt.want {
+			t.Errorf("%s: IsPaywalled = %s, want %s", tt.name, info.IsPaywalled, tt.want)
+		}
+		if !slices.Equal(info.Selectors, tt.selectors) {
+			t.Errorf("%s: Selectors = %q, want %q", tt.name, info.Selectors, tt.selectors)
+		}
+		if !slices.Equal(info.Signals, tt.signals) {
+			t.Errorf("%s: Signals = %q, want %q", tt.name, info.Signals, tt.signals)
+		}
+	}
+}
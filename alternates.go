@@ -0,0 +1,44 @@
+package htmlmetadata
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Alternate is a language-specific version of a page declared with
+// rel="alternate" hreflang="...".
+type Alternate struct {
+	// Hreflang is the language tag as written, or "x-default" for the
+	// fallback page.
+	Hreflang string `json:"hreflang"`
+	// Href is the alternate's address, absolute when the document base is known.
+	Href string `json:"href"`
+	// Valid reports whether Hreflang is "x-default" or a well-formed BCP 47
+	// tag. Malformed entries are returned rather than dropped so audits can
+	// flag them.
+	Valid bool `json:"valid"`
+}
+
+// Alternates returns the hreflang alternates declared by the page, in
+// document order.
+func (r *Result) Alternates() []Alternate {
+	var alts []Alternate
+	for _, link := range r.Links {
+		if !link.HasRel("alternate") || link.Hreflang == "" {
+			continue
+		}
+		tag := strings.TrimSpace(link.Hreflang)
+		alts = append(alts, Alternate{Hreflang: tag, Href: link.Href, Valid: validHreflang(tag)})
+	}
+	return alts
+}
+
+// validHreflang reports whether tag is acceptable as an hreflang value.
+func validHreflang(tag string) bool {
+	if strings.EqualFold(tag, "x-default") {
+		return true
+	}
+	_, err := language.Parse(tag)
+	return err == nil
+}
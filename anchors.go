@@ -0,0 +1,89 @@
+package htmlmetadata
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Anchor is an <a href> link of the page, as collected with WithAnchors.
+type Anchor struct {
+	// URL is the absolute link target, without its #fragment.
+	URL string `json:"url"`
+	// Text is the text of the link, with whitespace collapsed.
+	Text string `json:"text,omitempty"`
+	// Rel is the raw rel attribute of the first link to URL.
+	Rel string `json:"rel,omitempty"`
+	// NoFollow reports that every link to URL has rel="nofollow".
+	NoFollow bool `json:"nofollow,omitempty"`
+}
+
+// WithAnchors collects the <a href> links of pages into Result.Anchors when
+// enabled, for crawlers that expand their frontier from the pages they
+// extract. Only links that resolve to absolute http or https URLs are kept,
+// so mailto: and javascript: links and relative links in documents without
+// a known base are dropped. Fragments are removed and each URL is listed
+// once, at its first occurrence. Links are not collected by ParseHead, nor
+// by WithScanFullDocument(false), which stop before the body.
+func WithAnchors(enabled bool) Option {
+	return func(e *Extractor) {
+		e.anchors = enabled
+	}
+}
+
+// Outlinks returns the URLs of Anchors a crawler may follow: those not
+// marked rel="nofollow", or none if the page's robots meta tags or
+// X-Robots-Tag headers say nofollow.
+func (r *Result) Outlinks() []string {
+	if r.Robots().NoFollow {
+		return nil
+	}
+	var urls []string
+	for _, a := range r.Anchors {
+		if !a.NoFollow {
+			urls = append(urls, a.URL)
+		}
+	}
+	return urls
+}
+
+// anchor handles an <a> element.
+func (c *collector) anchor(n *html.Node) {
+	href := strings.TrimSpace(attrValue(n.Attr, "href"))
+	if href == "" {
+		return
+	}
+	a := Anchor{URL: href, Rel: attrValue(n.Attr, "rel")}
+	a.Text = truncateUTF8(strings.Join(strings.Fields(textContent(n)), " "), c.e.maxContentLength)
+	a.NoFollow = LinkTag{Rel: a.Rel}.HasRel("nofollow")
+	c.res.Anchors = append(c.res.Anchors, a)
+}
+
+// finishAnchors resolves the collected anchors against base, dropping those
+// that are not http or https and merging repeats of a URL.
+func (c *collector) finishAnchors(base *url.URL) {
+	anchors := c.res.Anchors[:0]
+	seen := make(map[string]int)
+	for _, a := range c.res.Anchors {
+		u, err := url.Parse(resolveURL(base, a.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+		// Host names are case-insensitive; paths are not
+		u.Host = strings.ToLower(u.Host)
+		u.Fragment, u.RawFragment = "", ""
+		a.URL = u.String()
+		if c.e.canonicalize {
+			a.URL = canonicalizeURL(a.URL, c.e.stripParams)
+		}
+		if i, ok := seen[a.URL]; ok {
+			// One followable link is enough to follow the URL
+			anchors[i].NoFollow = anchors[i].NoFollow && a.NoFollow
+			continue
+		}
+		seen[a.URL] = len(anchors)
+		anchors = append(anchors, a)
+	}
+	c.res.Anchors = anchors
+}
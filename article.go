@@ -0,0 +1,106 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Article holds the authorship and dating of a page, consolidated from the
+// OpenGraph article namespace and the plain author and date meta names.
+type Article struct {
+	// Authors lists every article:author in document order, or the author
+	// meta names if there is no article:author.
+	Authors []string `json:"authors,omitempty"`
+	// PublishedTime is article:published_time, falling back to the date meta
+	// name. ModifiedTime is article:modified_time, falling back to
+	// og:updated_time. Times that are missing or cannot be parsed are zero,
+	// and left out of the JSON encoding.
+	PublishedTime  time.Time `json:"published_time,omitempty"`
+	ModifiedTime   time.Time `json:"modified_time,omitempty"`
+	ExpirationTime time.Time `json:"expiration_time,omitempty"`
+	Section        string    `json:"section,omitempty"`
+	// Tags lists every article:tag in document order.
+	Tags []string `json:"tags,omitempty"`
+	// Warnings lists dates that could not be parsed.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// dateLayouts are tried in order when parsing article dates. ISO 8601 comes
+// first; the rest are forms seen on real pages.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"2 January 2006",
+}
+
+// parseDate parses an article date in one of dateLayouts. Times without an
+// offset are taken as UTC.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// ParseArticle collects the article:* entries, and the author and date meta
+// names, from tags into an Article.
+func ParseArticle(tags []MetaTag) Article {
+	var a Article
+	var authors []string
+	var date, updated string
+	setTime := func(dst *time.Time, name, value string) {
+		if !dst.IsZero() || value == "" {
+			return
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			a.Warnings = append(a.Warnings, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		*dst = t
+	}
+	for _, tag := range tags {
+		if tag.Source == SourceHTTPEquiv {
+			continue
+		}
+		switch strings.ToLower(tag.Name) {
+		case "article:author":
+			a.Authors = append(a.Authors, tag.Content)
+		case "author":
+			authors = append(authors, tag.Content)
+		case "article:published_time":
+			setTime(&a.PublishedTime, tag.Name, tag.Content)
+		case "article:modified_time":
+			setTime(&a.ModifiedTime, tag.Name, tag.Content)
+		case "article:expiration_time":
+			setTime(&a.ExpirationTime, tag.Name, tag.Content)
+		case "article:section":
+			setFirst(&a.Section, tag.Content)
+		case "article:tag":
+			a.Tags = append(a.Tags, tag.Content)
+		case "date":
+			setFirst(&date, tag.Content)
+		case "og:updated_time":
+			setFirst(&updated, tag.Content)
+		}
+	}
+	if len(a.Authors) == 0 {
+		a.Authors = authors
+	}
+	setTime(&a.PublishedTime, "date", date)
+	setTime(&a.ModifiedTime, "og:updated_time", updated)
+	return a
+}
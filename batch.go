@@ -0,0 +1,155 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// ExtractAll fetches every URL in urls using at most concurrency simultaneous
+// requests and returns one Result per URL, in input order. Failures are
+// reported per URL in Result.Err rather than aborting the batch. If ctx is
+// canceled, URLs not yet fetched get ctx.Err() as their Err and ExtractAll
+// returns ctx.Err().
+func (e *Extractor) ExtractAll(ctx context.Context, urls []string, concurrency int) ([]Result, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(urls); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = e.extractOne(ctx, urls[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(urls); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := next; i < len(urls); i++ {
+			results[i] = Result{Err: err}
+		}
+		return results, err
+	}
+	return results, nil
+}
+
+// ExtractStream fetches the URLs received from urls using at most concurrency
+// simultaneous requests and sends each Result on the returned channel as soon
+// as it completes, so results arrive in completion order rather than input
+// order. Failures are reported in Result.Err; when no response was received,
+// URL holds the requested URL. The channel is closed once urls is closed and
+// drained and every fetch has finished.
+//
+// If ctx is canceled, ExtractStream stops reading urls, abandons results the
+// caller is not ready to receive and closes the channel once the workers
+// have returned, so no goroutine outlives it; a sender on urls must watch
+// ctx as well.
+func (e *Extractor) ExtractStream(ctx context.Context, urls <-chan string, concurrency int) <-chan Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	out := make(chan Result)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var rawURL string
+				select {
+				case u, ok := <-urls:
+					if !ok {
+						return
+					}
+					rawURL = u
+				case <-ctx.Done():
+					return
+				}
+				res := e.extractOne(ctx, rawURL)
+				if res.URL == "" {
+					res.URL = rawURL
+				}
+				select {
+				case out <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// extractOne fetches rawURL and folds any error into the Result.
+func (e *Extractor) extractOne(ctx context.Context, rawURL string) Result {
+	res, err := e.ExtractResponseContext(ctx, rawURL)
+	if res == nil {
+		return Result{Err: err}
+	}
+	res.Err = err
+	return *res
+}
+
+// ExtractAllFromReader extracts the meta tags of each document in a stream
+// holding several, such as the records of an archive. split divides the
+// stream into documents as for a bufio.Scanner: each token it returns is
+// parsed as one document. The result holds one slice of tags per document, in
+// stream order.
+//
+// A document larger than the maximum body size fails with ErrBodyTooLarge. On
+// any error, the tags of the documents before the failing one are returned
+// along with it.
+func (e *Extractor) ExtractAllFromReader(r io.Reader, split bufio.SplitFunc) ([][]MetaTag, error) {
+	sc := bufio.NewScanner(r)
+	limit := e.maxBodySize
+	if limit <= 0 || limit > math.MaxInt-1 {
+		limit = math.MaxInt - 1
+	}
+	// The scanner needs room for one byte past a full token to see its end
+	size := int(limit) + 1
+	sc.Buffer(make([]byte, 0, min(size, 64<<10)), size)
+	sc.Split(split)
+
+	var docs [][]MetaTag
+	for sc.Scan() {
+		tags, err := e.extractMetaTags(bytes.NewReader(sc.Bytes()))
+		if err != nil {
+			return docs, fmt.Errorf("document %d: %w", len(docs), err)
+		}
+		docs = append(docs, tags)
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = ErrBodyTooLarge
+		}
+		return docs, fmt.Errorf("document %d: %w", len(docs), err)
+	}
+	return docs, nil
+}
@@ -0,0 +1,164 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultMaxBodySize is the largest response body read unless
+// WithMaxBodySize overrides it.
+const DefaultMaxBodySize = 10 << 20 // 10 MiB
+
+// limitReader reads from r until more than n bytes have been consumed, at
+// which point it fails with ErrBodyTooLarge instead of truncating silently.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	// Allow one byte past the limit so an exactly-sized body is not rejected.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+// limitBody caps r at max bytes. A non-positive max disables the limit.
+func limitBody(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &limitReader{r: r, n: max}
+}
+
+// decodeBody undoes the Content-Encoding of a response body. Transports that
+// decompressed the body themselves remove the header, in which case r is
+// returned unchanged. Multiple codings are removed in reverse order of
+// application.
+func decodeBody(r io.Reader, contentEncoding string) (io.Reader, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		var err error
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(r)
+		case "deflate":
+			r, err = newDeflateReader(r)
+		case "br":
+			r = brotli.NewReader(r)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding: %s", coding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s body: %w", codings[i], err)
+		}
+	}
+	return r, nil
+}
+
+// newDeflateReader reads a "deflate" body, which should be zlib-wrapped but is
+// sent as a raw DEFLATE stream by some servers.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// A zlib header declares method 8 and is a multiple of 31 as a big-endian uint16.
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// blankReader records whether anything but ASCII whitespace was read through
+// it.
+type blankReader struct {
+	r       io.Reader
+	content bool
+}
+
+func (b *blankReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if !b.content && len(bytes.TrimLeft(p[:n], asciiSpace)) > 0 {
+		b.content = true
+	}
+	return n, err
+}
+
+// sniffContentType returns the media type of the body in r as
+// http.DetectContentType determines it from its first 512 bytes, for
+// responses without a Content-Type. A body declaring the XHTML namespace
+// there is reported as application/xhtml+xml, which DetectContentType would
+// call text/xml. An empty or blank body is reported as "", so it fails as
+// an empty document rather than as one of the wrong type. The returned
+// reader replaces r.
+func sniffContentType(r io.Reader) (io.Reader, string) {
+	br := bufio.NewReaderSize(r, 512)
+	preview, _ := br.Peek(512)
+	if len(bytes.TrimLeft(preview, asciiSpace)) == 0 {
+		return br, ""
+	}
+	if bytes.Contains(preview, []byte(xhtmlNamespace)) {
+		return br, "application/xhtml+xml"
+	}
+	return br, http.DetectContentType(preview)
+}
+
+// truncationReader ends the stream cleanly where the body was cut short, as
+// net/http reports with io.ErrUnexpectedEOF when a connection closes before
+// the declared Content-Length or the final chunk, so the part received can
+// still be parsed. truncated records that this happened. Transports other
+// than net/http's may end a short body with a plain io.EOF, so at the end of
+// the stream the raw bytes read are also compared with want, the declared
+// Content-Length, if it is not negative.
+type truncationReader struct {
+	r         io.Reader
+	raw       *countingReader
+	want      int64
+	truncated bool
+}
+
+func (t *truncationReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		t.truncated = true
+		err = io.EOF
+	case err == io.EOF && t.want >= 0 && t.raw.n < t.want:
+		t.truncated = true
+	}
+	return n, err
+}
@@ -0,0 +1,96 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compress encodes data with the writer newWriter returns.
+func compress(t *testing.T, data string, newWriter func(io.Writer) io.WriteCloser) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestContentEncodings(t *testing.T) {
+	const doc = `<html><head><title>Compressed</title></head></html>`
+	gzipped := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zlibbed := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+	rawDeflate := func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}
+	brotlied := func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }
+	tests := []struct {
+		name, encoding, body string
+	}{
+		{"identity", "", doc},
+		{"gzip", "gzip", compress(t, doc, gzipped)},
+		{"x-gzip", "x-gzip", compress(t, doc, gzipped)},
+		{"zlib deflate", "deflate", compress(t, doc, zlibbed)},
+		{"raw deflate", "deflate", compress(t, doc, rawDeflate)},
+		{"brotli", "br", compress(t, doc, brotlied)},
+		// Codings are removed in reverse order of application
+		{"gzip then brotli", "gzip, br", compress(t, compress(t, doc, gzipped), brotlied)},
+	}
+	for _, tt := range tests {
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp := htmlResponse(req, "text/html", tt.body)
+			if tt.encoding != "" {
+				resp.Header.Set("Content-Encoding", tt.encoding)
+			}
+			return resp, nil
+		})
+		res, err := New(WithTransport(transport)).ExtractResponse("https://example.com/")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if res.Title != "Compressed" {
+			t.Errorf("%s: Title = %q", tt.name, res.Title)
+		}
+		if res.BytesRead != int64(len(tt.body)) {
+			t.Errorf("%s: BytesRead = %d, want the %d bytes on the wire", tt.name, res.BytesRead, len(tt.body))
+		}
+	}
+}
+
+func TestUnsupportedContentEncoding(t *testing.T) {
+	_, err := decodeBody(strings.NewReader("data"), "compress")
+	if err == nil || !strings.Contains(err.Error(), "unsupported content encoding: compress") {
+		t.Errorf("err = %v, want an unsupported encoding error", err)
+	}
+}
+
+func TestUndecodableBodyKeepsResponse(t *testing.T) {
+	for _, encoding := range []string{"compress", "gzip"} {
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp := htmlResponse(req, "text/html", "not gzip")
+			resp.Header.Set("Content-Encoding", encoding)
+			resp.Header.Set("ETag", `"v1"`)
+			return resp, nil
+		})
+		res, err := New(WithTransport(transport)).ExtractResponse("https://example.com/page")
+		if err == nil {
+			t.Fatalf("%s: no error", encoding)
+		}
+		if res == nil || res.StatusCode != http.StatusOK || res.URL != "https://example.com/page" || res.ETag != `"v1"` {
+			t.Errorf("%s: Result = %+v, want the response details", encoding, res)
+		}
+	}
+}
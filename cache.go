@@ -0,0 +1,97 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+)
+
+// CachedResponse is a stored copy of a fetched page.
+type CachedResponse struct {
+	// URL is the final URL of the page after redirects.
+	URL         string
+	ContentType string
+	// ETag and LastModified are the response validators, if the server sent
+	// any. Entries with validators are revalidated with a conditional request
+	// before use; entries without are used as-is.
+	ETag         string
+	LastModified string
+	// Body is the response body with any Content-Encoding removed.
+	Body []byte
+	// Header holds the response header fields that affect extraction, listed
+	// in cachedHeaders, such as Link. Other fields, cookies among them, are
+	// not stored.
+	Header http.Header
+}
+
+// cachedHeaders are the response header fields stored with a cached body.
+var cachedHeaders = []string{"Link", "X-Robots-Tag"}
+
+// cacheHeader copies the fields of h listed in cachedHeaders.
+func cacheHeader(h http.Header) http.Header {
+	var kept http.Header
+	for _, key := range cachedHeaders {
+		if values := h.Values(key); len(values) > 0 {
+			if kept == nil {
+				kept = make(http.Header)
+			}
+			kept[key] = append([]string(nil), values...)
+		}
+	}
+	return kept
+}
+
+// Cache stores fetched pages by requested URL. Implementations must be safe
+// for concurrent use.
+type Cache interface {
+	Get(url string) (*CachedResponse, bool)
+	Set(url string, resp *CachedResponse)
+}
+
+// MemoryCache is an unbounded in-memory Cache.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CachedResponse
+}
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*CachedResponse)}
+}
+
+// Get returns the entry stored for url.
+func (c *MemoryCache) Get(url string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	resp, ok := c.entries[url]
+	return resp, ok
+}
+
+// Set stores resp for url, replacing any previous entry.
+func (c *MemoryCache) Set(url string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = resp
+}
+
+// parseCached parses a cached page as if it had just been fetched.
+func (e *Extractor) parseCached(ctx context.Context, entry *CachedResponse) (*Result, error) {
+	res, err := e.parseContext(ctx, bytes.NewReader(entry.Body), entry.ContentType, entry.URL, entry.Header)
+	if res == nil {
+		return nil, err
+	}
+	res.URL, res.StatusCode, res.ContentType = entry.URL, http.StatusOK, entry.ContentType
+	res.ETag, res.LastModified = entry.ETag, entry.LastModified
+	res.FromCache = true
+	if e.retainBody {
+		res.Body = entry.Body
+	}
+	if err != nil {
+		return res, err
+	}
+	if len(bytes.TrimLeft(entry.Body, asciiSpace)) == 0 {
+		return res, ErrEmptyDocument
+	}
+	return res, nil
+}
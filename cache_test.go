@@ -0,0 +1,172 @@
+package htmlmetadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// versionedServer serves a page whose title and ETag are the current
+// version, answering 304 to requests that carry the current ETag. It records
+// the conditional headers of every request.
+type versionedServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	version    string
+	validators bool
+	requests   []http.Header
+}
+
+func newVersionedServer(t *testing.T, validators bool) *versionedServer {
+	s := &versionedServer{version: "v1", validators: validators}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r.Header.Clone())
+		etag := `"` + s.version + `"`
+		if s.validators {
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Link", `</style.css>; rel="preload"; as="style"`)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte("<title>" + s.version + "</title>"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *versionedServer) set(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+func (s *versionedServer) sent() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	srv := newVersionedServer(t, true)
+	cache := NewMemoryCache()
+	e := New(WithCache(cache))
+
+	first, err := e.ExtractResponse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.FromCache || first.Title != "v1" {
+		t.Fatalf("first fetch: FromCache = %v, Title = %q", first.FromCache, first.Title)
+	}
+	entry, ok := cache.Get(srv.URL)
+	if !ok {
+		t.Fatal("a 200 response with validators was not stored")
+	}
+	if entry.ETag != `"v1"` || entry.LastModified != "Mon, 01 Jan 2024 00:00:00 GMT" || string(entry.Body) != "<title>v1</title>" {
+		t.Errorf("stored %+v", entry)
+	}
+	if entry.Header.Get("Link") == "" || entry.Header.Get("Set-Cookie") != "" {
+		t.Errorf("stored header %v, want Link kept and cookies dropped", entry.Header)
+	}
+
+	// Unchanged: the server answers 304 and the cached copy is parsed
+	second, err := e.ExtractResponse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := srv.sent()
+	if len(sent) != 2 {
+		t.Fatalf("%d requests, want a revalidation", len(sent))
+	}
+	if got := sent[1].Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q", got)
+	}
+	if got := sent[1].Get("If-Modified-Since"); got != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", got)
+	}
+	if !second.FromCache || second.Title != "v1" || second.StatusCode != http.StatusOK {
+		t.Errorf("after 304: FromCache = %v, Title = %q, StatusCode = %d", second.FromCache, second.Title, second.StatusCode)
+	}
+	if len(second.Links) == 0 {
+		t.Error("the cached Link header was not applied")
+	}
+
+	// Changed: the new page replaces the stale entry
+	srv.set("v2")
+	third, err := e.ExtractResponse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.FromCache || third.Title != "v2" {
+		t.Errorf("after a change: FromCache = %v, Title = %q", third.FromCache, third.Title)
+	}
+	if entry, _ := cache.Get(srv.URL); entry.ETag != `"v2"` || string(entry.Body) != "<title>v2</title>" {
+		t.Errorf("entry not replaced: %+v", entry)
+	}
+}
+
+func TestCacheWithoutValidators(t *testing.T) {
+	srv := newVersionedServer(t, false)
+	e := New(WithCache(NewMemoryCache()))
+	for i := 0; i < 3; i++ {
+		res, err := e.ExtractResponse(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.FromCache != (i > 0) || res.Title != "v1" {
+			t.Errorf("fetch %d: FromCache = %v, Title = %q", i, res.FromCache, res.Title)
+		}
+	}
+	// Entries without validators are used as-is, even after a change
+	srv.set("v2")
+	res, err := e.ExtractResponse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(srv.sent()); got != 1 || res.Title != "v1" {
+		t.Errorf("%d requests, Title = %q, want one request and the stored page", got, res.Title)
+	}
+}
+
+func TestCacheSkipsUnsuccessfulResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<title>Missing</title>"))
+	}))
+	defer srv.Close()
+	cache := NewMemoryCache()
+	e := New(WithCache(cache), WithAcceptStatus(func(int) bool { return true }))
+	res, err := e.ExtractResponse(srv.URL)
+	if err != nil || res.Title != "Missing" {
+		t.Fatalf("Title = %q, err = %v", res.Title, err)
+	}
+	if _, ok := cache.Get(srv.URL); ok {
+		t.Error("a 404 response was stored")
+	}
+}
+
+func TestConditionalBypassesCache(t *testing.T) {
+	srv := newVersionedServer(t, true)
+	cache := NewMemoryCache()
+	e := New(WithCache(cache))
+	if _, err := e.ExtractResponse(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.ExtractConditional(context.Background(), srv.URL, `"v1"`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.NotModified || res.FromCache || res.Title != "" {
+		t.Errorf("NotModified = %v, FromCache = %v, Title = %q, want a bare 304", res.NotModified, res.FromCache, res.Title)
+	}
+}
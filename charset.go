@@ -0,0 +1,110 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// sniffLen is how much of the body is inspected for a charset declaration,
+// matching the HTML encoding sniffing algorithm.
+const sniffLen = 1024
+
+// newUTF8Reader wraps r so that it yields UTF-8. The source encoding is taken
+// from a byte order mark, then the charset parameter of contentType, then a
+// <meta charset> or http-equiv Content-Type declaration, then fallback, and
+// finally defaults to UTF-8. Only the first sniffLen bytes are buffered to
+// decide; the rest of r streams through as it is read.
+func newUTF8Reader(r io.Reader, contentType string, fallback encoding.Encoding) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	preview, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	// A byte order mark overrides any declared encoding and is not content
+	if enc, n := bomEncoding(preview); n > 0 {
+		br.Discard(n)
+		if enc == nil {
+			return br, nil
+		}
+		return transform.NewReader(br, enc.NewDecoder()), nil
+	}
+
+	enc := detectEncoding(preview, contentType, fallback)
+	if enc == nil || enc == encoding.Nop {
+		return br, nil
+	}
+	return transform.NewReader(br, enc.NewDecoder()), nil
+}
+
+// bomEncoding returns the encoding indicated by a byte order mark at the start
+// of preview, or nil for UTF-8, and the length of the mark. n is 0 if there is
+// no mark.
+func bomEncoding(preview []byte) (enc encoding.Encoding, n int) {
+	switch {
+	case bytes.HasPrefix(preview, []byte{0xEF, 0xBB, 0xBF}):
+		return nil, 3
+	case bytes.HasPrefix(preview, []byte{0xFE, 0xFF}):
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), 2
+	case bytes.HasPrefix(preview, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), 2
+	}
+	return nil, 0
+}
+
+// detectEncoding returns the encoding for a document starting with preview, or
+// nil if it should be read as UTF-8. fallback, if not nil, is used when the
+// document declares no encoding.
+func detectEncoding(preview []byte, contentType string, fallback encoding.Encoding) encoding.Encoding {
+	enc, _, certain := charset.DetermineEncoding(preview, contentType)
+	if certain {
+		// HTTP header; a BOM has already been handled by newUTF8Reader
+		return enc
+	}
+	enc, declared := declaredEncoding(preview)
+	if !declared && fallback != nil {
+		return fallback
+	}
+	return enc
+}
+
+// declaredEncoding returns the encoding named by a meta declaration in
+// preview, or nil for UTF-8, and whether there was a declaration at all.
+//
+// charset.DetermineEncoding falls back to windows-1252 when nothing is
+// declared, which is indistinguishable from a page declaring windows-1252. To
+// tell the two apart the preview is reduced to ASCII, which the meta prescan
+// never depends on, and a valid UTF-8 sequence is appended (followed by ASCII
+// so it is not discarded as a partial rune): without a declaration the
+// heuristic then settles on UTF-8. A second look at the bare ASCII preview,
+// for which the heuristic picks windows-1252, separates an explicit UTF-8
+// declaration from none.
+func declaredEncoding(preview []byte) (encoding.Encoding, bool) {
+	const marker = "é "
+	if len(preview) > sniffLen-len(marker) {
+		preview = preview[:sniffLen-len(marker)]
+	}
+	probe := make([]byte, len(preview), sniffLen)
+	for i, b := range preview {
+		if b >= 0x80 {
+			b = ' '
+		}
+		probe[i] = b
+	}
+	ascii := probe
+	probe = append(probe, marker...)
+
+	enc, name, _ := charset.DetermineEncoding(probe, "")
+	if name != "utf-8" {
+		return enc, true
+	}
+	_, name, _ = charset.DetermineEncoding(ascii, "")
+	return nil, name == "utf-8"
+}
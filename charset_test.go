@@ -0,0 +1,202 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// shiftJIS encodes s as Shift-JIS, failing the test if it cannot.
+func shiftJIS(t *testing.T, s string) string {
+	t.Helper()
+	out, err := japanese.ShiftJIS.NewEncoder().String(s)
+	if err != nil {
+		t.Fatalf("encoding %q: %v", s, err)
+	}
+	return out
+}
+
+func TestShiftJISRoundTrip(t *testing.T) {
+	const title, description = "日本語のタイトル", "説明文です"
+	head := `<title>` + title + `</title><meta name="description" content="` + description + `">`
+	tests := []struct {
+		name, contentType, doc string
+	}{
+		{"header", "text/html; charset=Shift_JIS", `<html><head>` + head},
+		{"meta charset", "text/html", `<html><head><meta charset="shift_jis">` + head},
+		{"http-equiv", "", `<html><head><meta http-equiv="Content-Type" content="text/html; charset=Shift_JIS">` + head},
+		// The header wins over a contradicting declaration
+		{"header over meta", "text/html; charset=shift_jis", `<html><head><meta charset="iso-8859-1">` + head},
+	}
+	for _, tt := range tests {
+		doc := shiftJIS(t, tt.doc)
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return htmlResponse(req, tt.contentType, doc), nil
+		})
+		res, err := New(WithTransport(transport)).ExtractResponse("https://example.com/")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.Title != title {
+			t.Errorf("%s: Title = %q, want %q", tt.name, res.Title, title)
+		}
+		if got := res.AsMap(FirstWins)["description"]; got != description {
+			t.Errorf("%s: description = %q, want %q", tt.name, got, description)
+		}
+	}
+}
+
+func TestDeclaredEncoding(t *testing.T) {
+	tests := []struct {
+		name, preview string
+		declared      bool
+		// want is the name of the declared encoding, "" for UTF-8
+		want string
+	}{
+		{"nothing declared", `<html><head><title>x</title>`, false, ""},
+		{"nothing declared, non-ASCII bytes", "<html><head><title>caf\xe9</title>", false, ""},
+		{"utf-8", `<meta charset="utf-8">`, true, ""},
+		{"windows-1252", `<meta charset="windows-1252">`, true, "windows-1252"},
+		{"iso-8859-1 is windows-1252", `<meta charset="iso-8859-1">`, true, "windows-1252"},
+		{"shift_jis", `<meta http-equiv="content-type" content="text/html; charset=shift_jis">`, true, "shift_jis"},
+		{"declaration past non-ASCII bytes", "<title>\x93\xfa</title><meta charset=\"shift_jis\">", true, "shift_jis"},
+	}
+	for _, tt := range tests {
+		enc, declared := declaredEncoding([]byte(tt.preview))
+		if declared != tt.declared {
+			t.Errorf("%s: declared = %v, want %v", tt.name, declared, tt.declared)
+		}
+		var want encoding.Encoding
+		if tt.want != "" {
+			want, _ = charset.Lookup(tt.want)
+		}
+		// Lookup and DetermineEncoding may return distinct values
+		if fmt.Sprint(enc) != fmt.Sprint(want) {
+			t.Errorf("%s: encoding %v, want %s", tt.name, enc, cmp.Or(tt.want, "UTF-8"))
+		}
+	}
+}
+
+func TestDefaultCharsetOnlyWithoutDeclaration(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"undeclared", "<title>caf\xe9</title>", "café"},
+		{"declared utf-8", "<meta charset=\"utf-8\"><title>café</title>", "café"},
+	}
+	for _, tt := range tests {
+		res := parseString(t, tt.doc, WithDefaultCharset("iso-8859-1"))
+		if res.Title != tt.want {
+			t.Errorf("%s: Title = %q, want %q", tt.name, res.Title, tt.want)
+		}
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	const doc = `<meta charset="iso-8859-1"><title>Ünïcödé</title>`
+	utf16 := func(bigEndian bool) string {
+		var b []byte
+		for _, r := range doc {
+			hi, lo := byte(r>>8), byte(r)
+			if bigEndian {
+				b = append(b, hi, lo)
+			} else {
+				b = append(b, lo, hi)
+			}
+		}
+		return string(b)
+	}
+	tests := []struct {
+		name, body string
+	}{
+		{"UTF-8", "\xef\xbb\xbf" + doc},
+		{"UTF-16LE", "\xff\xfe" + utf16(false)},
+		{"UTF-16BE", "\xfe\xff" + utf16(true)},
+	}
+	for _, tt := range tests {
+		// The mark overrides both the header and the meta declaration
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return htmlResponse(req, "text/html; charset=windows-1252", tt.body), nil
+		})
+		res, err := New(WithTransport(transport)).ExtractResponse("https://example.com/")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.Title != "Ünïcödé" {
+			t.Errorf("%s: Title = %q", tt.name, res.Title)
+		}
+		if res.Charset != "iso-8859-1" {
+			t.Errorf("%s: the meta tag was not read intact, Charset = %q", tt.name, res.Charset)
+		}
+	}
+}
+
+// memoryWatchReader yields a windows-1252 document of about size bytes,
+// repeating a paragraph between a head and a final meta tag, and records the
+// largest heap seen while it is read.
+type memoryWatchReader struct {
+	size, read int64
+	pending    []byte
+	done       bool
+	nextSample int64
+	maxHeap    uint64
+}
+
+func (m *memoryWatchReader) Read(p []byte) (int, error) {
+	if len(m.pending) == 0 {
+		switch {
+		case m.done:
+			return 0, io.EOF
+		case m.read == 0:
+			m.pending = []byte("<html><head><meta charset=\"windows-1252\"><title>Caf\xe9</title></head><body>")
+		case m.read < m.size:
+			m.pending = bytes.Repeat([]byte("<p>Caf\xe9 cr\xe8me br\xfbl\xe9e</p>\n"), 1024)
+		default:
+			m.pending = []byte("<meta name=\"last\" content=\"fin\xe9\"></body></html>")
+			m.done = true
+		}
+	}
+	n := copy(p, m.pending)
+	m.pending = m.pending[n:]
+	m.read += int64(n)
+	if m.read >= m.nextSample {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		m.maxHeap = max(m.maxHeap, stats.HeapAlloc)
+		m.nextSample += 4 << 20
+	}
+	return n, nil
+}
+
+func TestStreamingLargeDocument(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reads 128 MiB")
+	}
+	const size = 128 << 20
+	runtime.GC()
+	r := &memoryWatchReader{size: size}
+	var last string
+	err := New().ExtractFunc(r, func(tag MetaTag) bool {
+		if tag.Name == "last" {
+			last = tag.Content
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ExtractFunc: %v", err)
+	}
+	if last != "finé" {
+		t.Errorf("final meta tag: got %q, want it transcoded from windows-1252", last)
+	}
+	if limit := uint64(32 << 20); r.maxHeap > limit {
+		t.Errorf("heap reached %d MiB streaming %d MiB, want under %d MiB", r.maxHeap>>20, size>>20, limit>>20)
+	}
+}
@@ -0,0 +1,65 @@
+package htmlmetadata
+
+import "strings"
+
+// Namespace returns the lowercase prefix of the tag's name, the part before
+// the first ":" or ".", such as "og" for og:image, "twitter" for
+// twitter:card or "dc" for DC.title. It returns "" for unprefixed names such
+// as description, and for http-equiv tags, whose names are header names.
+func (t MetaTag) Namespace() string {
+	if t.Source == SourceHTTPEquiv {
+		return ""
+	}
+	i := strings.IndexAny(t.Name, ":.")
+	if i <= 0 {
+		return ""
+	}
+	return strings.ToLower(t.Name[:i])
+}
+
+// IsOpenGraph reports whether the tag is an OpenGraph og:* tag. OpenGraph
+// specifies the property attribute, but tags that use name instead count as
+// well, since ParseOpenGraph accepts them.
+func (t MetaTag) IsOpenGraph() bool {
+	return t.hasPrefix("og:")
+}
+
+// IsTwitter reports whether the tag is a Twitter Card twitter:* tag, given by
+// either name or property.
+func (t MetaTag) IsTwitter() bool {
+	return t.hasPrefix("twitter:")
+}
+
+// IsDublinCore reports whether the tag is a Dublin Core DC.* or DCTERMS.* tag,
+// in either the dotted or the colon form.
+func (t MetaTag) IsDublinCore() bool {
+	if t.Source == SourceHTTPEquiv {
+		return false
+	}
+	_, ok := dublinCoreElement(t.Name)
+	return ok
+}
+
+// hasPrefix reports whether the tag's name starts with prefix, ignoring case.
+func (t MetaTag) hasPrefix(prefix string) bool {
+	return t.Source != SourceHTTPEquiv && hasPrefixFold(t.Name, prefix)
+}
+
+// vocabularyName returns the name under which tag belongs to the vocabulary
+// with prefix, such as "og:". Name holds only one of a tag's name and
+// property attributes, so the og: property of a tag such as
+// <meta name="description" property="og:description"> may be in the other.
+func vocabularyName(tag MetaTag, prefix string) string {
+	for _, name := range []string{tag.Name, tag.Property, tag.NameAttr} {
+		if strings.HasPrefix(name, prefix) {
+			return name
+		}
+	}
+	return tag.Name
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case, and has
+// more after it.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) > len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,61 @@
+package htmlmetadata
+
+import "testing"
+
+func TestClassifyNamespaces(t *testing.T) {
+	const doc = `<html><head>
+<meta property="og:title" content="1">
+<meta name="og:description" content="2">
+<meta property="OG:Image" content="3">
+<meta name="twitter:card" content="4">
+<meta property="twitter:site" content="5">
+<meta name="DC.title" content="6">
+<meta name="dcterms:created" content="7">
+<meta name="DCTERMS.issued" content="8">
+<meta name="description" content="9">
+<meta name="og:" content="10">
+<meta name="article:author" content="11">
+<meta http-equiv="og:fake" content="12">
+</head></html>`
+	tests := []struct {
+		content, namespace         string
+		openGraph, twitter, dublin bool
+	}{
+		{"1", "og", true, false, false},
+		{"2", "og", true, false, false},
+		{"3", "og", true, false, false},
+		{"4", "twitter", false, true, false},
+		{"5", "twitter", false, true, false},
+		{"6", "dc", false, false, true},
+		{"7", "dcterms", false, false, true},
+		{"8", "dcterms", false, false, true},
+		{"9", "", false, false, false},
+		// A bare prefix names nothing in the vocabulary
+		{"10", "og", false, false, false},
+		{"11", "article", false, false, false},
+		// http-equiv names are header names, never vocabulary terms
+		{"12", "", false, false, false},
+	}
+	res := parseString(t, doc)
+	if len(res.Tags) != len(tests) {
+		t.Fatalf("got %d tags, want %d", len(res.Tags), len(tests))
+	}
+	for i, tt := range tests {
+		tag := res.Tags[i]
+		if tag.Content != tt.content {
+			t.Fatalf("tag %d has content %q, want %q", i, tag.Content, tt.content)
+		}
+		if got := tag.Namespace(); got != tt.namespace {
+			t.Errorf("%s: Namespace() = %q, want %q", tag.Name, got, tt.namespace)
+		}
+		if got := tag.IsOpenGraph(); got != tt.openGraph {
+			t.Errorf("%s: IsOpenGraph() = %v", tag.Name, got)
+		}
+		if got := tag.IsTwitter(); got != tt.twitter {
+			t.Errorf("%s: IsTwitter() = %v", tag.Name, got)
+		}
+		if got := tag.IsDublinCore(); got != tt.dublin {
+			t.Errorf("%s: IsDublinCore() = %v", tag.Name, got)
+		}
+	}
+}
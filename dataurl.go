@@ -0,0 +1,90 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// dataTransport answers requests for data: URLs (RFC 2397) from the URL
+// itself and passes everything else to next. data: URLs only get this far
+// when allowed with WithAllowedSchemes.
+type dataTransport struct {
+	next http.RoundTripper
+}
+
+func (t *dataTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "data" {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	mediaType, data, err := decodeDataURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {mediaType}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// decodeDataURL returns the media type and payload of a data: URL. The
+// payload is percent-decoded and, if marked ;base64, base64-decoded. A
+// missing media type defaults to text/plain;charset=US-ASCII.
+func decodeDataURL(u *url.URL) (string, []byte, error) {
+	raw := strings.TrimPrefix(u.String(), "data:")
+	header, payload, ok := strings.Cut(raw, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: data URL without a comma", ErrInvalidURL)
+	}
+	header = strings.TrimSpace(header)
+
+	isBase64 := false
+	if len(header) >= len(";base64") && strings.EqualFold(header[len(header)-len(";base64"):], ";base64") {
+		isBase64 = true
+		header = header[:len(header)-len(";base64")]
+	}
+	mediaType, err := url.PathUnescape(header)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if mediaType == "" || strings.HasPrefix(mediaType, ";") {
+		mediaType = "text/plain" + mediaType
+		if !strings.Contains(strings.ToLower(mediaType), "charset=") {
+			mediaType += ";charset=US-ASCII"
+		}
+	}
+
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	if !isBase64 {
+		return mediaType, []byte(text), nil
+	}
+	// Whitespace and padding are commonly sloppy in hand-written data URLs
+	text = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(asciiSpace+"=", r) {
+			return -1
+		}
+		return r
+	}, text)
+	data, err := base64.RawStdEncoding.DecodeString(text)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: bad base64 payload: %w", ErrInvalidURL, err)
+	}
+	return mediaType, data, nil
+}
@@ -0,0 +1,17 @@
+package htmlmetadata
+
+// Dedup returns tags with exact duplicates removed, keeping the first
+// occurrence of each tag in document order. Tags are duplicates only when
+// every field matches; tags that merely share a name are kept.
+func Dedup(tags []MetaTag) []MetaTag {
+	seen := make(map[MetaTag]bool, len(tags))
+	out := tags[:0:0]
+	for _, tag := range tags {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
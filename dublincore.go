@@ -0,0 +1,97 @@
+package htmlmetadata
+
+import "strings"
+
+// DublinCore holds the Dublin Core elements of a page.
+type DublinCore struct {
+	Title       string   `json:"title,omitempty"`
+	Creators    []string `json:"creators,omitempty"`
+	Subjects    []string `json:"subjects,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	// Contributors lists every contributor in document order.
+	Contributors []string `json:"contributors,omitempty"`
+	Date         string   `json:"date,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Format       string   `json:"format,omitempty"`
+	Identifier   string   `json:"identifier,omitempty"`
+	Source       string   `json:"source,omitempty"`
+	Language     string   `json:"language,omitempty"`
+	Relation     string   `json:"relation,omitempty"`
+	Coverage     string   `json:"coverage,omitempty"`
+	Rights       string   `json:"rights,omitempty"`
+	// Extra holds Dublin Core terms without a dedicated field, such as
+	// DCTERMS.issued or DC.date.modified, keyed by the tag name as written. If
+	// a name repeats, the first value is kept.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// dublinCorePrefixes are the lowercase name prefixes recognized as Dublin Core.
+var dublinCorePrefixes = []string{"dcterms.", "dcterms:", "dc.", "dc:"}
+
+// dublinCoreElement returns the lowercase element name of a Dublin Core meta
+// name such as "DC.title" or "dcterms:creator".
+func dublinCoreElement(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, prefix := range dublinCorePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return lower[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// ParseDublinCore collects the DC.* and DCTERMS.* entries from tags into a
+// DublinCore. Both the dotted ("DC.title") and colon ("dc:title") forms are
+// recognized, case-insensitively. Creator, subject and contributor repeat
+// commonly and are collected into slices; other elements keep their first
+// value.
+func ParseDublinCore(tags []MetaTag) DublinCore {
+	dc := DublinCore{Extra: make(map[string]string)}
+	for _, tag := range tags {
+		if tag.Source == SourceHTTPEquiv {
+			continue
+		}
+		element, ok := dublinCoreElement(tag.Name)
+		if !ok {
+			continue
+		}
+		switch element {
+		case "title":
+			setFirst(&dc.Title, tag.Content)
+		case "creator":
+			dc.Creators = append(dc.Creators, tag.Content)
+		case "subject":
+			dc.Subjects = append(dc.Subjects, tag.Content)
+		case "description":
+			setFirst(&dc.Description, tag.Content)
+		case "publisher":
+			setFirst(&dc.Publisher, tag.Content)
+		case "contributor":
+			dc.Contributors = append(dc.Contributors, tag.Content)
+		case "date":
+			setFirst(&dc.Date, tag.Content)
+		case "type":
+			setFirst(&dc.Type, tag.Content)
+		case "format":
+			setFirst(&dc.Format, tag.Content)
+		case "identifier":
+			setFirst(&dc.Identifier, tag.Content)
+		case "source":
+			setFirst(&dc.Source, tag.Content)
+		case "language":
+			setFirst(&dc.Language, tag.Content)
+		case "relation":
+			setFirst(&dc.Relation, tag.Content)
+		case "coverage":
+			setFirst(&dc.Coverage, tag.Content)
+		case "rights":
+			setFirst(&dc.Rights, tag.Content)
+		default:
+			if _, ok := dc.Extra[tag.Name]; !ok {
+				dc.Extra[tag.Name] = tag.Content
+			}
+		}
+	}
+	return dc
+}
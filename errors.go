@@ -0,0 +1,116 @@
+package htmlmetadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrInvalidScheme is returned for URLs whose scheme the Extractor cannot fetch.
+// See WithAllowedSchemes.
+var ErrInvalidScheme = errors.New("invalid URL scheme")
+
+// ErrInvalidURL is returned for URLs that cannot be parsed, or that lack a
+// host where the scheme requires one.
+var ErrInvalidURL = errors.New("invalid URL")
+
+// ErrParse is wrapped by errors returned when a document cannot be parsed as
+// HTML.
+var ErrParse = errors.New("failed to parse HTML")
+
+// PanicError is returned, along with the Result collected up to that point,
+// when parsing a document panics, so that one pathological page fails on its
+// own instead of crashing the process. Parse, ParseHead and the fetching
+// methods return the partial Result, whose relative URLs may be unresolved;
+// ExtractFromReader and the other methods returning tags return the tags
+// found so far. A panic in a TagVisitor is reported the same way. It matches
+// ErrParse.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: panic: %v", ErrParse, e.Value)
+}
+
+// Is reports whether target is ErrParse.
+func (e *PanicError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// ErrNotHTML is returned when a response's Content-Type is not an accepted
+// HTML type or, for a response without one, the body does not sniff as one.
+var ErrNotHTML = errors.New("response is not HTML")
+
+// ErrEmptyDocument is returned, along with the Result describing the
+// response, when a response body is empty or holds nothing but whitespace. It
+// tells a server that served no content apart from a page without metadata.
+// Documents passed to Parse and the other reader-based methods are not
+// checked.
+var ErrEmptyDocument = errors.New("empty document")
+
+// StatusError is returned when a server answers with an unexpected HTTP
+// status. Use errors.As to inspect the code, for example to retry 5xx
+// responses.
+type StatusError struct {
+	Code int
+	// URL is the final URL of the response.
+	URL string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.Code)
+}
+
+// ErrTooManyRedirects is matched by errors returned when a redirect chain
+// loops or exceeds the limit set with WithMaxRedirects. Use errors.As with a
+// *RedirectError to inspect the chain.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// RedirectError describes a redirect chain that was abandoned.
+type RedirectError struct {
+	// Chain lists the URLs visited in order, ending with the redirect target
+	// that was not requested.
+	Chain []string
+	// Loop reports that some URL in the chain was visited more than once.
+	Loop bool
+}
+
+func (e *RedirectError) Error() string {
+	msg := fmt.Sprintf("stopped after %d redirects", len(e.Chain)-2)
+	if e.Loop {
+		msg += " in a loop"
+	}
+	return fmt.Sprintf("%s: %s", msg, strings.Join(e.Chain, " -> "))
+}
+
+// Is reports whether target is ErrTooManyRedirects.
+func (e *RedirectError) Is(target error) bool {
+	return target == ErrTooManyRedirects
+}
+
+// ErrDisallowedByRobots is returned when WithRobotsPolicy is enabled and the
+// site's robots.txt disallows the URL.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// ErrTimeout is wrapped by errors returned when a request exceeds the
+// configured timeout or the caller's context deadline.
+var ErrTimeout = errors.New("request timed out")
+
+// ErrBodyTooLarge is returned when a response body exceeds the configured
+// maximum size.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// wrapTimeout tags err with ErrTimeout when it was caused by a timeout.
+func wrapTimeout(err error) error {
+	var ne net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
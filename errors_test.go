@@ -0,0 +1,82 @@
+package htmlmetadata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// panicVisitor panics on the first <link> element. No known document makes
+// the parser or the extractors panic, so a visitor stands in for one; the
+// edge cases that came closest are kept in TestMalformedDocumentsDoNotPanic.
+type panicVisitor struct{}
+
+func (panicVisitor) Visit(n *html.Node) {
+	if n.DataAtom == atom.Link {
+		panic("visitor failed")
+	}
+}
+
+func TestPanicInVisitorKeepsPartialResult(t *testing.T) {
+	const doc = `<html><head>
+<meta name="description" content="before">
+<meta property="og:title" content="also before">
+<link rel="canonical" href="https://example.com/">
+<meta name="keywords" content="after">
+</head></html>`
+	check := func(method string, tags []MetaTag, err error) {
+		t.Helper()
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) || !errors.Is(err, ErrParse) {
+			t.Fatalf("%s: err = %v, want a *PanicError matching ErrParse", method, err)
+		}
+		if panicErr.Value != "visitor failed" || len(panicErr.Stack) == 0 {
+			t.Errorf("%s: Value = %v, %d bytes of stack", method, panicErr.Value, len(panicErr.Stack))
+		}
+		if len(tags) != 2 || tags[0].Name != "description" || tags[1].Name != "og:title" {
+			t.Errorf("%s: got tags %v, want the two before the panic", method, tags)
+		}
+	}
+	e := New(WithVisitor(panicVisitor{}), WithTransport(NewStaticTransport(map[string]string{
+		"https://example.com/": doc,
+	})))
+
+	tags, err := e.ExtractFromString(doc)
+	check("ExtractFromString", tags, err)
+	tags, err = e.Extract("https://example.com/")
+	check("Extract", tags, err)
+	res, err := e.ExtractResponse("https://example.com/")
+	if res == nil {
+		t.Fatalf("ExtractResponse: no Result with %v", err)
+	}
+	check("ExtractResponse", res.Tags, err)
+}
+
+func TestMalformedDocumentsDoNotPanic(t *testing.T) {
+	tests := []struct {
+		name, doc string
+	}{
+		{"unclosed", `<html><head><meta name="description" content="open`},
+		{"nul bytes", "<meta name=\"a\x00b\" content=\"c\x00d\"><title>\x00</title>"},
+		{"foreign content", `<svg><meta name="description" content="svg"><title>Icon</title></svg><math><link rel="canonical" href="/m"></math>`},
+		{"template", `<template><meta property="og:title" content="hidden"><link rel="icon"></template>`},
+		{"frameset", `<frameset><meta name="robots" content="noindex"><frame src="a"></frameset>`},
+		{"itemref cycle", `<div itemscope itemref="b" id="a"><span itemprop="x">1</span></div>
+<div itemscope itemref="a" id="b" itemprop="y"></div>`},
+		{"broken json-ld", `<script type="application/ld+json">{"@type": [}</script><script type="application/ld+json"></script>`},
+		{"empty attributes", `<meta name content><meta property=""><link rel href><base href><html lang>`},
+		{"bad urls", `<base href="http://[::1"><link rel="canonical" href="%zz"><meta property="og:image" content="http://a b/">`},
+		{"deep nesting", strings.Repeat("<div><span>", 2000) + `<meta name="description" content="deep">`},
+		{"huge attribute", `<meta name="description" content="` + strings.Repeat("x", 1<<20) + `">`},
+	}
+	for _, tt := range tests {
+		_, err := New().ExtractFromString(tt.doc)
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			t.Errorf("%s: panic %v\n%s", tt.name, panicErr.Value, panicErr.Stack)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package htmlmetadata
+
+import (
+	"mime"
+	"strings"
+)
+
+// Feed is a syndication feed advertised by a page.
+type Feed struct {
+	// URL is the feed address, absolute when the document base is known.
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	// Type is the feed's media type, e.g. application/rss+xml.
+	Type string `json:"type,omitempty"`
+}
+
+// feedTypes are the media types recognized as feeds on rel="alternate" links.
+var feedTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	// JSON Feed was originally advertised with the generic JSON type
+	"application/json": true,
+}
+
+// Feeds returns the RSS, Atom and JSON feeds advertised through
+// rel="alternate" links, in document order.
+func (r *Result) Feeds() []Feed {
+	var feeds []Feed
+	for _, link := range r.Links {
+		if !link.HasRel("alternate") {
+			continue
+		}
+		mediaType, _, err := mime.ParseMediaType(link.Type)
+		if err != nil || !feedTypes[strings.ToLower(mediaType)] {
+			continue
+		}
+		feeds = append(feeds, Feed{URL: link.Href, Title: link.Title, Type: mediaType})
+	}
+	return feeds
+}
@@ -0,0 +1,398 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExtractResponseContext is like ExtractResponse but carries ctx on the
+// outbound request.
+func (e *Extractor) ExtractResponseContext(ctx context.Context, rawURL string) (*Result, error) {
+	return e.extractResponse(ctx, rawURL, "", "")
+}
+
+// ExtractConditional is like ExtractResponseContext but revalidates a copy of
+// the page the caller already has, sending etag as If-None-Match and
+// lastModified as If-Modified-Since; either may be empty. If the server
+// answers 304 Not Modified, the Result has NotModified set and carries no
+// page data, and the error is nil. Otherwise the page is extracted as usual,
+// and Result.ETag and Result.LastModified hold the validators to keep for
+// next time. The configured Cache, if any, is bypassed when validators are
+// given.
+func (e *Extractor) ExtractConditional(ctx context.Context, rawURL, etag, lastModified string) (*Result, error) {
+	return e.extractResponse(ctx, rawURL, etag, lastModified)
+}
+
+// extractResponse fetches and extracts rawURL, revalidating with the given
+// validators if any.
+func (e *Extractor) extractResponse(ctx context.Context, rawURL, etag, lastModified string) (*Result, error) {
+	if err := e.validateURL(rawURL); err != nil {
+		return nil, err
+	}
+	if err := e.checkRobotsURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := e.newRequest(ctx, http.MethodGet, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conditional := etag != "" || lastModified != ""
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	var cached *CachedResponse
+	if e.cache != nil && !conditional {
+		if entry, ok := e.cache.Get(rawURL); ok {
+			if entry.ETag == "" && entry.LastModified == "" {
+				return e.parseCached(ctx, entry)
+			}
+			cached = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
+
+	start := time.Now()
+	if e.preflightHead {
+		if res, err := e.preflight(ctx, rawURL); err != nil {
+			return res, err
+		}
+	}
+
+	// Fetch the page
+	resp, err := e.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	fetchDuration := time.Since(start)
+
+	var res *Result
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		res, err = e.parseCached(ctx, cached)
+	case resp.StatusCode == http.StatusNotModified && conditional:
+		res = responseResult(resp)
+		res.NotModified = true
+	default:
+		res, err = e.fromResponse(ctx, resp, rawURL)
+	}
+	if res != nil {
+		res.FetchDuration = fetchDuration
+	}
+	return res, err
+}
+
+// ExtractFromResponse extracts meta tags from a response fetched by the
+// caller, applying the same status, content type, decoding and body size
+// handling as ExtractResponse. The caller remains responsible for closing
+// resp.Body. Parsing is bounded by the context of resp.Request, if any.
+func (e *Extractor) ExtractFromResponse(resp *http.Response) (*Result, error) {
+	ctx := context.Background()
+	if resp.Request != nil {
+		ctx = resp.Request.Context()
+	}
+	return e.fromResponse(ctx, resp, "")
+}
+
+// fromResponse validates resp and parses its body. If cacheKey is not empty
+// and a Cache is configured, a successful body is stored under it.
+func (e *Extractor) fromResponse(ctx context.Context, resp *http.Response, cacheKey string) (*Result, error) {
+	meta := responseResult(resp)
+	if e.stoppedAtRedirect(resp.StatusCode) {
+		if loc, err := resp.Location(); err == nil {
+			meta.Location = loc.String()
+		}
+		return meta, nil
+	}
+	if !e.acceptsStatus(resp.StatusCode) {
+		return meta, &StatusError{Code: resp.StatusCode, URL: meta.URL}
+	}
+
+	if !e.acceptsContentType(meta.ContentType) {
+		return meta, fmt.Errorf("%w: %s", ErrNotHTML, meta.ContentType)
+	}
+
+	counter := &countingReader{r: resp.Body}
+	decoded, err := decodeBody(counter, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return meta, err
+	}
+	cut := &truncationReader{r: decoded, raw: counter, want: bodyLength(resp)}
+	blank := &blankReader{r: limitBody(cut, e.maxBodySize)}
+	var body io.Reader = blank
+	if meta.ContentType == "" && !e.forceHTML {
+		var sniffed string
+		body, sniffed = sniffContentType(body)
+		if sniffed != "" && !e.acceptsContentType(sniffed) {
+			return meta, fmt.Errorf("%w: no Content-Type, sniffed %s", ErrNotHTML, sniffed)
+		}
+	}
+
+	// The body is buffered whole when it must outlive the parse
+	var data []byte
+	cacheable := e.cache != nil && cacheKey != "" && resp.StatusCode == http.StatusOK
+	if cacheable || e.retainBody {
+		data, err = io.ReadAll(body)
+		if err != nil {
+			return meta, wrapTimeout(fmt.Errorf("failed to read body: %w", err))
+		}
+		body = bytes.NewReader(data)
+	}
+	if cacheable && !cut.truncated {
+		e.cache.Set(cacheKey, &CachedResponse{
+			URL:          meta.URL,
+			ContentType:  meta.ContentType,
+			ETag:         meta.ETag,
+			LastModified: meta.LastModified,
+			Body:         data,
+			Header:       cacheHeader(resp.Header),
+		})
+	}
+
+	// Parse the HTML
+	res, err := e.parseContext(ctx, body, meta.ContentType, meta.URL, resp.Header)
+	if res == nil {
+		return meta, wrapTimeout(err)
+	}
+	res.URL, res.StatusCode, res.ContentType = meta.URL, meta.StatusCode, meta.ContentType
+	res.RedirectChain = meta.RedirectChain
+	if len(e.clientWarnings) > 0 {
+		res.Warnings = append(slices.Clip(e.clientWarnings), res.Warnings...)
+		e.hooks.parseWarnings(e.clientWarnings)
+	}
+	res.ETag, res.LastModified = meta.ETag, meta.LastModified
+	res.BytesRead = counter.n
+	if e.retainBody {
+		res.Body = data
+	}
+	if cut.truncated {
+		res.Truncated = true
+		n := len(res.Warnings)
+		if resp.ContentLength >= 0 {
+			res.warnf("body truncated after %d of %d bytes", counter.n, resp.ContentLength)
+		} else {
+			res.warnf("body truncated after %d bytes", counter.n)
+		}
+		// The parse has reported its own warnings already
+		e.hooks.parseWarnings(res.Warnings[n:])
+	}
+	if err != nil {
+		// A PanicError, with what was found before the panic
+		return res, err
+	}
+	if !blank.content {
+		return res, ErrEmptyDocument
+	}
+	return res, nil
+}
+
+// bodyLength returns the length resp.Body should have, or -1 if it is not
+// known. HEAD and 304 responses may declare the Content-Length of a body
+// they do not carry.
+func bodyLength(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusNotModified || (resp.Request != nil && resp.Request.Method == http.MethodHead) {
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// responseResult returns a Result describing resp, without parsing its body.
+func responseResult(resp *http.Response) *Result {
+	res := &Result{
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		res.URL = resp.Request.URL.String()
+		res.RedirectChain = redirectChain(resp.Request)
+	}
+	return res
+}
+
+// redirectChain returns the URLs of the requests that led to req, ending
+// with req itself. The client links each redirected request to the response
+// that caused it.
+func redirectChain(req *http.Request) []string {
+	var chain []string
+	for r := req; r != nil && r.URL != nil; {
+		chain = append(chain, r.URL.String())
+		if r.Response == nil {
+			break
+		}
+		r = r.Response.Request
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// validateURL checks that rawURL is an absolute URL with an allowed scheme.
+func (e *Extractor) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	// Schemes are case-insensitive. url.Parse already lowercases the URL's,
+	// but the allowed list is as the caller wrote it
+	scheme := strings.ToLower(u.Scheme)
+	allowed := false
+	for _, s := range e.schemes {
+		if strings.EqualFold(scheme, s) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrInvalidScheme, rawURL)
+	}
+	if (scheme == "http" || scheme == "https") && u.Host == "" {
+		return fmt.Errorf("%w: missing host in %s", ErrInvalidURL, rawURL)
+	}
+	return nil
+}
+
+// newRequest builds a request carrying the configured headers.
+func (e *Extractor) newRequest(ctx context.Context, method, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for key, values := range e.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if e.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", e.userAgent)
+	}
+	// robots.txt is plain text, so only pages get the HTML preference
+	if e.accept != "" && req.Header.Get("Accept") == "" && req.URL.Path != "/robots.txt" {
+		req.Header.Set("Accept", e.accept)
+	}
+	if e.referer != "" {
+		req.Header.Set("Referer", e.referer)
+	}
+	if e.origin != "" {
+		req.Header.Set("Origin", e.origin)
+	}
+	return req, nil
+}
+
+// do sends req, retrying network errors and retryable statuses according to
+// the retry policy. The caller's context error is reported in preference to
+// the transport's when the context ended.
+func (e *Extractor) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		e.hooks.fetchStart(req.URL.String())
+		start := time.Now()
+		resp, err := e.client.Do(req)
+		if err != nil {
+			e.hooks.fetchDone(req.URL.String(), 0, time.Since(start), err)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			err = fmt.Errorf("failed to fetch URL: %w", wrapTimeout(err))
+			if attempt >= e.maxAttempts || ctx.Err() != nil || errors.Is(err, ErrTooManyRedirects) || errors.Is(err, ErrDisallowedByRobots) {
+				return nil, err
+			}
+		} else {
+			e.hooks.fetchDone(req.URL.String(), resp.StatusCode, time.Since(start), nil)
+			if attempt >= e.maxAttempts || !e.shouldRetry(resp.StatusCode) {
+				return resp, nil
+			}
+			discard(resp)
+		}
+
+		if err := sleep(ctx, e.retryDelay(attempt, resp)); err != nil {
+			return nil, fmt.Errorf("failed to fetch URL: %w", wrapTimeout(err))
+		}
+	}
+}
+
+// preflight issues a HEAD request for rawURL and fails early if the headers
+// show the body would be rejected anyway, because it is not HTML or exceeds
+// the size limit. Any other outcome, including servers that do not support
+// HEAD, returns a nil error so the GET goes ahead.
+func (e *Extractor) preflight(ctx context.Context, rawURL string) (*Result, error) {
+	req, err := e.newRequest(ctx, http.MethodHead, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.do(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil
+	}
+	meta := responseResult(resp)
+	if !e.acceptsContentType(meta.ContentType) {
+		return meta, fmt.Errorf("%w: %s", ErrNotHTML, meta.ContentType)
+	}
+	if e.maxBodySize > 0 && resp.ContentLength > e.maxBodySize {
+		return meta, fmt.Errorf("%w: Content-Length %d", ErrBodyTooLarge, resp.ContentLength)
+	}
+	return nil, nil
+}
+
+// acceptsStatus reports whether a response with the given status code should
+// be parsed.
+func (e *Extractor) acceptsStatus(code int) bool {
+	if e.acceptStatus != nil {
+		return e.acceptStatus(code)
+	}
+	return code == http.StatusOK
+}
+
+// acceptsContentType reports whether a body served as contentType should be
+// parsed. A missing Content-Type is given the benefit of the doubt until the
+// body is sniffed.
+func (e *Extractor) acceptsContentType(contentType string) bool {
+	if contentType == "" || e.forceHTML {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range e.contentTypes {
+		if strings.EqualFold(mediaType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRedirect reports whether code is a redirect status that carries a Location.
+func isRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
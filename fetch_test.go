@@ -0,0 +1,218 @@
+package htmlmetadata
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// htmlResponse returns a 200 response to req with body, served with the given
+// Content-Type header, or none if contentType is empty.
+func htmlResponse(req *http.Request, contentType, body string) *http.Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func TestTruncatedBody(t *testing.T) {
+	const body = `<title>Short</title>`
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := htmlResponse(req, "text/html", body)
+		// The transport ends the body early with a plain io.EOF
+		resp.ContentLength = 5000
+		return resp, nil
+	})
+	var hooked []string
+	e := New(WithTransport(transport), WithHooks(Hooks{
+		OnParseWarning: func(msg string) { hooked = append(hooked, msg) },
+	}))
+	res, err := e.ExtractResponse("https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	if !res.Truncated {
+		t.Error("Truncated = false for a body short of its Content-Length")
+	}
+	want := "body truncated after 20 of 5000 bytes"
+	if len(res.Warnings) != 1 || res.Warnings[0] != want {
+		t.Errorf("Warnings = %q, want [%q]", res.Warnings, want)
+	}
+	if len(hooked) != 1 || hooked[0] != want {
+		t.Errorf("OnParseWarning got %q, want [%q]", hooked, want)
+	}
+	if res.Title != "Short" {
+		t.Errorf("Title = %q, want the part received parsed", res.Title)
+	}
+}
+
+func TestCompleteBodyNotTruncated(t *testing.T) {
+	e := New(WithTransport(NewStaticTransport(map[string]string{
+		"https://example.com/": `<title>Complete</title>`,
+	})))
+	res, err := e.ExtractResponse("https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	if res.Truncated || len(res.Warnings) != 0 {
+		t.Errorf("Truncated = %v, Warnings = %q for a complete body", res.Truncated, res.Warnings)
+	}
+}
+
+func TestMissingContentTypeIsSniffed(t *testing.T) {
+	tests := []struct {
+		name, body string
+		force      bool
+		wantErr    error
+	}{
+		{"html", `<!DOCTYPE html><html><head><title>Sniffed</title></head></html>`, false, nil},
+		{"xhtml", `<?xml version="1.0"?><html xmlns="http://www.w3.org/1999/xhtml"><head><title>Sniffed</title></head></html>`, false, nil},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", false, ErrNotHTML},
+		{"pdf", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n", false, ErrNotHTML},
+		{"png forced", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", true, nil},
+		{"blank", "  \n", false, ErrEmptyDocument},
+	}
+	for _, tt := range tests {
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return htmlResponse(req, "", tt.body), nil
+		})
+		res, err := New(WithTransport(transport), WithForceHTML(tt.force)).ExtractResponse("https://example.com/")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && !tt.force && res.Title != "Sniffed" {
+			t.Errorf("%s: Title = %q", tt.name, res.Title)
+		}
+		if res != nil && res.ContentType != "" {
+			t.Errorf("%s: ContentType = %q, want the missing header left empty", tt.name, res.ContentType)
+		}
+	}
+}
+
+func TestMixedCaseSchemes(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Scheme == "https" {
+			return htmlResponse(req, "text/html", `<title>Secure</title>`), nil
+		}
+		return htmlResponse(req, "text/html", `<title>Plain</title>`), nil
+	})
+	tests := []struct {
+		url     string
+		schemes []string
+		want    string
+		wantErr error
+	}{
+		{"HTTP://example.com/", nil, "Plain", nil},
+		{"Https://example.com/", nil, "Secure", nil},
+		{"hTTpS://example.com/", nil, "Secure", nil},
+		{"https://example.com/", []string{"HTTPS"}, "Secure", nil},
+		{"HTTP://example.com/", []string{"HTTPS"}, "", ErrInvalidScheme},
+		{"FTP://example.com/", nil, "", ErrInvalidScheme},
+		{"HTTPS:///no-host", nil, "", ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		opts := []Option{WithTransport(transport)}
+		if tt.schemes != nil {
+			opts = append(opts, WithAllowedSchemes(tt.schemes...))
+		}
+		res, err := New(opts...).ExtractResponse(tt.url)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && res.Title != tt.want {
+			t.Errorf("%s: Title = %q, want %q", tt.url, res.Title, tt.want)
+		}
+	}
+}
+
+func TestRetainBody(t *testing.T) {
+	const body = `<title>Kept</title>`
+	transport := NewStaticTransport(map[string]string{"https://example.com/": body})
+	for _, enabled := range []bool{false, true} {
+		res, err := New(WithTransport(transport), WithRetainBody(enabled)).ExtractResponse("https://example.com/")
+		if err != nil {
+			t.Fatalf("enabled=%v: %v", enabled, err)
+		}
+		if got := string(res.Body); enabled && got != body || !enabled && res.Body != nil {
+			t.Errorf("enabled=%v: Body = %q", enabled, got)
+		}
+	}
+}
+
+func TestPreflightHead(t *testing.T) {
+	tests := []struct {
+		name       string
+		headStatus int
+		headType   string
+		headLength string
+		wantErr    error
+		wantGET    bool
+	}{
+		{"html", http.StatusOK, "text/html", "", nil, true},
+		{"not html", http.StatusOK, "image/png", "", ErrNotHTML, false},
+		{"too large", http.StatusOK, "text/html", "10485760", ErrBodyTooLarge, false},
+		{"HEAD not allowed", http.StatusMethodNotAllowed, "image/png", "", nil, true},
+		{"HEAD not implemented", http.StatusNotImplemented, "", "", nil, true},
+	}
+	for _, tt := range tests {
+		var methods []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			if r.Method == http.MethodHead {
+				if tt.headType != "" {
+					w.Header().Set("Content-Type", tt.headType)
+				}
+				if tt.headLength != "" {
+					w.Header().Set("Content-Length", tt.headLength)
+				}
+				w.WriteHeader(tt.headStatus)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<title>Fetched</title>"))
+		}))
+		res, err := New(WithPreflightHead(true), WithMaxBodySize(1<<20)).ExtractResponse(srv.URL)
+		srv.Close()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		want := []string{http.MethodHead}
+		if tt.wantGET {
+			want = append(want, http.MethodGet)
+		}
+		if !slices.Equal(methods, want) {
+			t.Errorf("%s: requests %q, want %q", tt.name, methods, want)
+		}
+		if tt.wantGET && res.Title != "Fetched" {
+			t.Errorf("%s: Title = %q", tt.name, res.Title)
+		}
+		if !tt.wantGET && (res == nil || res.StatusCode != http.StatusOK) {
+			t.Errorf("%s: want the HEAD response described, got %+v", tt.name, res)
+		}
+	}
+}
@@ -2,4 +2,9 @@ module github.com/go-i2p/go-html-metadata
 
 go 1.23.5
 
-require golang.org/x/net v0.37.0
+require (
+	github.com/andybalholm/brotli v1.1.1
+	golang.org/x/net v0.37.0
+	golang.org/x/text v0.23.0
+	golang.org/x/time v0.11.0
+)
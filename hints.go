@@ -0,0 +1,50 @@
+package htmlmetadata
+
+import (
+	"slices"
+	"strings"
+)
+
+// ResourceHint is a <link> that asks the browser to fetch a resource, or
+// connect to its origin, before the page needs it.
+type ResourceHint struct {
+	// Rel is the hint: "preload", "prefetch", "preconnect" or "dns-prefetch".
+	Rel string `json:"rel"`
+	// URL is the resource, or for preconnect and dns-prefetch the origin,
+	// absolute when the document base is known.
+	URL string `json:"url"`
+	// As is the destination of a preload, such as "font" or "style".
+	As string `json:"as,omitempty"`
+	// Type is the media type of the resource, if declared.
+	Type string `json:"type,omitempty"`
+	// CrossOrigin is "anonymous" or "use-credentials" if the request is made
+	// in CORS mode, "" if not. A preloaded font must be fetched in CORS mode
+	// for the page to use it.
+	CrossOrigin string `json:"crossorigin,omitempty"`
+}
+
+// resourceHintRels are the relations, lowercased, that declare resource hints.
+var resourceHintRels = []string{"preload", "prefetch", "preconnect", "dns-prefetch"}
+
+// ResourceHints returns the preload, prefetch, preconnect and dns-prefetch
+// hints declared through <link> elements and Link response headers, in
+// document order, headers first. A link with several of these relations
+// yields a hint for each.
+func (r *Result) ResourceHints() []ResourceHint {
+	var hints []ResourceHint
+	for _, link := range r.Links {
+		for _, rel := range strings.Fields(strings.ToLower(link.Rel)) {
+			if !slices.Contains(resourceHintRels, rel) {
+				continue
+			}
+			hints = append(hints, ResourceHint{
+				Rel:         rel,
+				URL:         link.Href,
+				As:          link.As,
+				Type:        link.Type,
+				CrossOrigin: link.CrossOrigin,
+			})
+		}
+	}
+	return hints
+}
@@ -0,0 +1,38 @@
+package htmlmetadata
+
+import "time"
+
+// Hooks are optional callbacks for observing an Extractor, for example to
+// feed logs or metrics. Nil fields are skipped, and nothing is reported
+// unless hooks are installed with WithHooks. Hooks may be called from several
+// goroutines at once.
+type Hooks struct {
+	// OnFetchStart is called before each HTTP request, including retries and
+	// HEAD preflights.
+	OnFetchStart func(url string)
+	// OnFetchDone is called after each HTTP request with the response status,
+	// or 0 and the error if no response arrived.
+	OnFetchDone func(url string, status int, elapsed time.Duration, err error)
+	// OnParseWarning is called for each entry added to Result.Warnings.
+	OnParseWarning func(msg string)
+}
+
+func (h *Hooks) fetchStart(url string) {
+	if h.OnFetchStart != nil {
+		h.OnFetchStart(url)
+	}
+}
+
+func (h *Hooks) fetchDone(url string, status int, elapsed time.Duration, err error) {
+	if h.OnFetchDone != nil {
+		h.OnFetchDone(url, status, elapsed, err)
+	}
+}
+
+func (h *Hooks) parseWarnings(msgs []string) {
+	if h.OnParseWarning != nil {
+		for _, msg := range msgs {
+			h.OnParseWarning(msg)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package htmlmetadata
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultHostCacheSize is how many hosts the Extractor keeps per-host state
+// for unless WithHostCacheSize overrides it.
+const DefaultHostCacheSize = 10000
+
+// WithHostCacheSize bounds the per-host state the Extractor keeps, the
+// robots.txt rules of WithRobotsPolicy and the limiters of WithRateLimit, to
+// n hosts each. Once the bound is reached, the state of the least recently
+// used host is dropped, so a long-running crawl across millions of hosts uses
+// constant memory while the hosts it keeps returning to stay cached. A
+// dropped host starts afresh: its robots.txt is fetched again and its
+// limiter begins with a full burst. A non-positive n removes the bound.
+func WithHostCacheSize(n int) Option {
+	return func(e *Extractor) {
+		e.hostCacheSize = n
+	}
+}
+
+// hostCache maps hosts to values of type V, holding at most size of them and
+// evicting the least recently used. A non-positive size removes the bound.
+// It is safe for concurrent use.
+type hostCache[V any] struct {
+	size int
+
+	mu sync.Mutex
+	// order holds a *hostCacheEntry for each host, most recently used first.
+	order *list.List
+	hosts map[string]*list.Element
+}
+
+type hostCacheEntry[V any] struct {
+	host  string
+	value V
+}
+
+func newHostCache[V any](size int) *hostCache[V] {
+	return &hostCache[V]{size: size, order: list.New(), hosts: make(map[string]*list.Element)}
+}
+
+// get returns the value stored for host, marking it as recently used. If
+// there is none, it stores and returns the value made by create.
+func (c *hostCache[V]) get(host string, create func() V) V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.hosts[host]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*hostCacheEntry[V]).value
+	}
+	entry := &hostCacheEntry[V]{host: host, value: create()}
+	c.hosts[host] = c.order.PushFront(entry)
+	if c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.hosts, oldest.Value.(*hostCacheEntry[V]).host)
+	}
+	return entry.value
+}
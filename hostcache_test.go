@@ -0,0 +1,69 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestHostCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newHostCache[int](3)
+	created := 0
+	get := func(host string) int {
+		return c.get(host, func() int {
+			created++
+			return created
+		})
+	}
+	hot := get("hot.example")
+	for i := 0; i < 100; i++ {
+		// The hot host is used between every cold one
+		get(fmt.Sprintf("cold%d.example", i))
+		if v := get("hot.example"); v != hot {
+			t.Fatalf("after %d cold hosts the hot host was evicted: value %d, want %d", i+1, v, hot)
+		}
+	}
+	if n := c.order.Len(); n != 3 || len(c.hosts) != 3 {
+		t.Errorf("cache holds %d entries, %d in the map, want 3", n, len(c.hosts))
+	}
+	// Only the two most recent cold hosts remain beside the hot one
+	before := created
+	get("cold99.example")
+	get("cold98.example")
+	if created != before {
+		t.Errorf("recent hosts were evicted: %d values created", created-before)
+	}
+	get("cold0.example")
+	if created != before+1 {
+		t.Errorf("an old host was kept")
+	}
+}
+
+func TestHostCacheUnbounded(t *testing.T) {
+	c := newHostCache[string](0)
+	for i := 0; i < 1000; i++ {
+		c.get(strconv.Itoa(i), func() string { return "v" })
+	}
+	if len(c.hosts) != 1000 {
+		t.Errorf("unbounded cache holds %d hosts, want 1000", len(c.hosts))
+	}
+}
+
+func TestHostCacheConcurrentGet(t *testing.T) {
+	c := newHostCache[*int](8)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.get(strconv.Itoa((g+i)%16), func() *int { return new(int) })
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := c.order.Len(); n != 8 || len(c.hosts) != 8 {
+		t.Errorf("cache holds %d entries, %d in the map, want 8", n, len(c.hosts))
+	}
+}
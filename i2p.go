@@ -0,0 +1,41 @@
+package htmlmetadata
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithI2P routes requests for .i2p hosts through transport while all other
+// hosts keep using the regular transport. transport is expected to reach I2P
+// eepsites, for example by dialing through a SAM bridge or by forwarding to a
+// router's HTTP proxy:
+//
+//	proxy, _ := url.Parse("http://127.0.0.1:4444")
+//	e := New(WithI2P(&http.Transport{Proxy: http.ProxyURL(proxy)}))
+//
+// Tunnel setup on I2P commonly takes tens of seconds, so pair this with a
+// generous WithTimeout; the timeout covers I2P requests like any other.
+func WithI2P(transport http.RoundTripper) Option {
+	return func(e *Extractor) {
+		e.i2pTransport = transport
+	}
+}
+
+// i2pRouter sends .i2p requests to i2p and everything else to clearnet.
+type i2pRouter struct {
+	i2p      http.RoundTripper
+	clearnet http.RoundTripper
+}
+
+func (t *i2pRouter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isI2PHost(req.URL.Hostname()) {
+		return t.i2p.RoundTrip(req)
+	}
+	return t.clearnet.RoundTrip(req)
+}
+
+// isI2PHost reports whether host is an I2P name such as example.i2p or a
+// .b32.i2p address.
+func isI2PHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), ".i2p")
+}
@@ -0,0 +1,116 @@
+package htmlmetadata
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IconSize is one entry of a link's sizes attribute.
+type IconSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Icon is a favicon or touch icon declared by a page.
+type Icon struct {
+	// URL is the icon address, absolute when the document base is known.
+	URL string `json:"url"`
+	// Rel is the relation that declared the icon, e.g. "icon" or
+	// "apple-touch-icon".
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	// Sizes lists the parsed dimensions. Any is set for sizes="any", used by
+	// scalable icons.
+	Sizes []IconSize `json:"sizes,omitempty"`
+	Any   bool       `json:"any,omitempty"`
+	// Fallback marks the implicit /favicon.ico returned when the page declares
+	// no icons.
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// MaxSize returns the largest width declared for the icon, or 0 if none is.
+func (i Icon) MaxSize() int {
+	largest := 0
+	for _, s := range i.Sizes {
+		if s.Width > largest {
+			largest = s.Width
+		}
+	}
+	return largest
+}
+
+// iconRels are the relations, lowercased, that declare icons.
+var iconRels = []string{"icon", "apple-touch-icon", "apple-touch-icon-precomposed"}
+
+// Icons returns the icons declared through rel="icon" (including the legacy
+// "shortcut icon") and rel="apple-touch-icon" links, in document order. If
+// there are none, it returns /favicon.ico relative to the document base,
+// marked as a Fallback.
+func (r *Result) Icons() []Icon {
+	var icons []Icon
+	for _, link := range r.Links {
+		rel, ok := iconRel(link)
+		if !ok {
+			continue
+		}
+		icon := Icon{URL: link.Href, Rel: rel, Type: link.Type}
+		icon.Sizes, icon.Any = parseSizes(link.Sizes)
+		icons = append(icons, icon)
+	}
+	if len(icons) == 0 {
+		icons = append(icons, Icon{URL: r.ResolveReference("/favicon.ico"), Rel: "icon", Fallback: true})
+	}
+	return icons
+}
+
+// LargestIcon returns the icon with the greatest declared size. Scalable icons
+// win over any fixed size; if no icon declares a size, the first is returned.
+func (r *Result) LargestIcon() Icon {
+	icons := r.Icons()
+	best := icons[0]
+	for _, icon := range icons[1:] {
+		switch {
+		case best.Any:
+		case icon.Any:
+			best = icon
+		case icon.MaxSize() > best.MaxSize():
+			best = icon
+		}
+	}
+	return best
+}
+
+// iconRel returns the icon relation of link, if it declares an icon.
+func iconRel(link LinkTag) (string, bool) {
+	for _, rel := range iconRels {
+		if link.HasRel(rel) {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+// parseSizes parses a sizes attribute such as "16x16 32x32" or "any".
+// Malformed entries are skipped.
+func parseSizes(sizes string) ([]IconSize, bool) {
+	var out []IconSize
+	var scalable bool
+	for _, field := range strings.Fields(sizes) {
+		field = strings.ToLower(field)
+		if field == "any" {
+			scalable = true
+			continue
+		}
+		w, h, ok := strings.Cut(field, "x")
+		if !ok {
+			continue
+		}
+		width, err1 := strconv.Atoi(w)
+		height, err2 := strconv.Atoi(h)
+		if err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+			continue
+		}
+		out = append(out, IconSize{Width: width, Height: height})
+	}
+	return out, scalable
+}
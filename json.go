@@ -0,0 +1,120 @@
+package htmlmetadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MarshalText encodes s as its attribute name, as returned by String.
+func (s AttrSource) MarshalText() ([]byte, error) {
+	switch s {
+	case SourceName, SourceProperty, SourceHTTPEquiv, SourceItemProp:
+		return []byte(s.String()), nil
+	}
+	return nil, fmt.Errorf("invalid AttrSource %d", int(s))
+}
+
+// UnmarshalText decodes an attribute name produced by MarshalText.
+func (s *AttrSource) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "name":
+		*s = SourceName
+	case "property":
+		*s = SourceProperty
+	case "http-equiv":
+		*s = SourceHTTPEquiv
+	case "itemprop":
+		*s = SourceItemProp
+	default:
+		return fmt.Errorf("invalid AttrSource %q", text)
+	}
+	return nil
+}
+
+// MarshalText encodes s as its name, as returned by String.
+func (s PaywallStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case PaywallUnknown, Paywalled, NotPaywalled:
+		return []byte(s.String()), nil
+	}
+	return nil, fmt.Errorf("invalid PaywallStatus %d", int(s))
+}
+
+// UnmarshalText decodes a name produced by MarshalText.
+func (s *PaywallStatus) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "unknown":
+		*s = PaywallUnknown
+	case "paywalled":
+		*s = Paywalled
+	case "free":
+		*s = NotPaywalled
+	default:
+		return fmt.Errorf("invalid PaywallStatus %q", text)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the Result as a JSON object whose keys are the
+// snake_case names in the struct tags, such as "url", "title", "tags" and
+// "links"; empty fields are omitted and durations are in nanoseconds. Three
+// derived keys are added: "opengraph" and "twitter" hold ParseOpenGraph and
+// ParseTwitterCard of the tags, when the page has any, and "error" holds the
+// text of Err. "raw_meta" holds RawMetaTags.
+//
+// The shape is stable: keys are only ever added, never renamed or removed.
+// Decoding it back into a Result restores the plain fields but not the
+// derived ones.
+func (r Result) MarshalJSON() ([]byte, error) {
+	// plain has Result's fields and tags but not this method
+	type plain Result
+	out := struct {
+		plain
+		OpenGraph *OpenGraph          `json:"opengraph,omitempty"`
+		Twitter   *TwitterCard        `json:"twitter,omitempty"`
+		RawMeta   []map[string]string `json:"raw_meta,omitempty"`
+		Error     string              `json:"error,omitempty"`
+	}{plain: plain(r), RawMeta: r.RawMetaTags()}
+
+	var hasOG, hasTwitter bool
+	for _, tag := range r.Tags {
+		hasOG = hasOG || tag.IsOpenGraph()
+		hasTwitter = hasTwitter || tag.IsTwitter()
+	}
+	if hasOG {
+		og := ParseOpenGraph(r.Tags)
+		out.OpenGraph = &og
+	}
+	if hasTwitter {
+		tc := ParseTwitterCard(r.Tags)
+		out.Twitter = &tc
+	}
+	if r.Err != nil {
+		out.Error = r.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// MarshalJSON encodes the Article with the keys in its struct tags, leaving
+// out the times that are zero rather than encoding them as year 1.
+func (a Article) MarshalJSON() ([]byte, error) {
+	// plain has Article's fields and tags but not this method
+	type plain Article
+	out := struct {
+		plain
+		PublishedTime  *time.Time `json:"published_time,omitempty"`
+		ModifiedTime   *time.Time `json:"modified_time,omitempty"`
+		ExpirationTime *time.Time `json:"expiration_time,omitempty"`
+	}{plain: plain(a)}
+	nonZero := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	out.PublishedTime = nonZero(a.PublishedTime)
+	out.ModifiedTime = nonZero(a.ModifiedTime)
+	out.ExpirationTime = nonZero(a.ExpirationTime)
+	return json.Marshal(out)
+}
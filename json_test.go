@@ -0,0 +1,181 @@
+package htmlmetadata
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// jsonKeys returns the JSON keys of the exported fields of struct type t.
+func jsonKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		keys = append(keys, cmp.Or(name, f.Name))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// TestJSONKeysStable guards the documented JSON shape: keys may be added
+// to these lists, but never renamed or removed.
+func TestJSONKeysStable(t *testing.T) {
+	tests := []struct {
+		typ  reflect.Type
+		want []string
+	}{
+		{reflect.TypeOf(Result{}), []string{
+			"amp_url", "anchors", "bytes_read", "charset", "content_type", "etag",
+			"fetch_duration_ns", "from_cache", "is_amp", "items", "jsonld", "jsonld_raw",
+			"lang", "last_modified", "links", "location", "not_modified", "parse_duration_ns",
+			"rdfa", "reading_time_ns", "redirect_chain", "refresh", "status_code", "tags",
+			"title", "truncated", "url", "warnings", "word_count", "x_robots_tag",
+		}},
+		{reflect.TypeOf(MetaTag{}), []string{"content", "itemprop", "media", "name", "name_attr", "property", "source"}},
+		{reflect.TypeOf(LinkTag{}), []string{"as", "crossorigin", "from_header", "href", "hreflang", "rel", "sizes", "title", "type"}},
+		{reflect.TypeOf(RobotsDirectives{}), []string{
+			"directives", "max_image_preview", "max_snippet", "max_video_preview", "no_archive",
+			"no_follow", "no_image_index", "no_index", "no_snippet", "no_translate",
+		}},
+		{reflect.TypeOf(AccessInfo{}), []string{"is_paywalled", "selectors", "signals"}},
+		{reflect.TypeOf(Article{}), []string{
+			"authors", "expiration_time", "modified_time", "published_time", "section", "tags", "warnings",
+		}},
+	}
+	for _, tt := range tests {
+		got := jsonKeys(tt.typ)
+		for _, key := range tt.want {
+			if !slices.Contains(got, key) {
+				t.Errorf("%s: key %q was renamed or removed", tt.typ.Name(), key)
+			}
+		}
+		for _, key := range got {
+			if !slices.Contains(tt.want, key) {
+				t.Errorf("%s: new key %q; add it to this test", tt.typ.Name(), key)
+			}
+		}
+	}
+}
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	const doc = `<!DOCTYPE html><html lang="en"><head>
+<meta charset="utf-8">
+<title>Round trip</title>
+<meta name="description" content="A page">
+<meta property="og:title" content="OG title">
+<meta property="og:image" content="/image.png">
+<meta name="twitter:card" content="summary">
+<meta http-equiv="refresh" content="30; url=/next">
+<link rel="canonical" href="/canonical">
+<script type="application/ld+json">{"@type": "Article", "headline": "Round trip"}</script>
+</head><body><div itemscope itemtype="https://schema.org/Thing"><span itemprop="name">Thing</span></div></body></html>`
+	e := New(WithTransport(NewStaticTransport(map[string]string{"https://example.com/page": doc})))
+	res, err := e.ExtractResponse("https://example.com/page")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	for _, key := range []string{"url", "title", "tags", "links", "jsonld", "items", "refresh", "opengraph", "twitter", "raw_meta"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("key %q missing from %s", key, data)
+		}
+	}
+	if !strings.Contains(string(fields["tags"]), `"source":"property"`) {
+		t.Errorf("AttrSource not encoded by name: %s", fields["tags"])
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal into Result: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Tags, res.Tags) || !reflect.DeepEqual(decoded.Links, res.Links) {
+		t.Errorf("tags or links changed in the round trip:\n%v\n%v", decoded.Tags, res.Tags)
+	}
+	if decoded.URL != res.URL || decoded.Title != res.Title || decoded.Lang != res.Lang || *decoded.Refresh != *res.Refresh {
+		t.Errorf("plain fields changed in the round trip: %+v", decoded)
+	}
+
+	// Encoding the decoded Result again gives the same JSON, except for
+	// raw_meta, which is derived from state JSON does not carry
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var againFields map[string]json.RawMessage
+	if err := json.Unmarshal(again, &againFields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	delete(fields, "raw_meta")
+	for key, value := range fields {
+		if string(againFields[key]) != string(value) {
+			t.Errorf("key %q: %s after the round trip, want %s", key, againFields[key], value)
+		}
+	}
+	for key := range againFields {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("key %q appeared after the round trip", key)
+		}
+	}
+}
+
+func TestPaywallStatusJSON(t *testing.T) {
+	for _, status := range []PaywallStatus{PaywallUnknown, Paywalled, NotPaywalled} {
+		data, err := json.Marshal(AccessInfo{IsPaywalled: status})
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `{"is_paywalled":"` + status.String() + `"}`; string(data) != want {
+			t.Errorf("got %s, want %s", data, want)
+		}
+		var decoded AccessInfo
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded.IsPaywalled != status {
+			t.Errorf("%s: decoded %v, %v", data, decoded.IsPaywalled, err)
+		}
+	}
+	if _, err := json.Marshal(PaywallStatus(7)); err == nil {
+		t.Error("Marshal accepted an invalid PaywallStatus")
+	}
+	var decoded AccessInfo
+	if err := json.Unmarshal([]byte(`{"is_paywalled":"maybe"}`), &decoded); err == nil {
+		t.Error("Unmarshal accepted an unknown status name")
+	}
+}
+
+func TestArticleJSON(t *testing.T) {
+	a := ParseArticle([]MetaTag{
+		{Name: "article:published_time", Property: "article:published_time", Content: "2024-05-01T09:30:00Z"},
+		{Name: "article:section", Property: "article:section", Content: "News"},
+	})
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"section":"News","published_time":"2024-05-01T09:30:00Z"}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	var decoded Article
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !decoded.PublishedTime.Equal(a.PublishedTime) || !decoded.ModifiedTime.IsZero() || decoded.Section != "News" {
+		t.Errorf("decoded %+v, want %+v", decoded, a)
+	}
+	if data, _ := json.Marshal(Article{}); string(data) != "{}" {
+		t.Errorf("empty Article encodes as %s", data)
+	}
+}
@@ -0,0 +1,45 @@
+package htmlmetadata
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// isJSONLDScript reports whether a <script> with attrs is of type
+// application/ld+json.
+func isJSONLDScript(attrs []html.Attribute) bool {
+	mediaType, _, err := mime.ParseMediaType(attrValue(attrs, "type"))
+	return err == nil && mediaType == "application/ld+json"
+}
+
+// addJSONLD records the raw text of a JSON-LD block and decodes it into
+// objects. A top-level array contributes each of its objects. Blocks that do
+// not decode are kept in JSONLDRaw but skipped with a warning.
+func (r *Result) addJSONLD(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	r.JSONLDRaw = append(r.JSONLDRaw, text)
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		r.warnf("malformed ld+json block skipped: %v", err)
+		return
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		r.JSONLD = append(r.JSONLD, v)
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				r.JSONLD = append(r.JSONLD, obj)
+			}
+		}
+	default:
+		r.warnf("ld+json block is not an object or array, skipped")
+	}
+}
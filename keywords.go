@@ -0,0 +1,40 @@
+package htmlmetadata
+
+import "strings"
+
+// DefaultKeywordSeparators are the characters Keywords splits on. Commas are
+// standard; some CMSes emit semicolons instead.
+const DefaultKeywordSeparators = ",;"
+
+// Keywords returns the entries of every name="keywords" tag, split on
+// DefaultKeywordSeparators.
+func Keywords(tags []MetaTag) []string {
+	return KeywordsSplit(tags, DefaultKeywordSeparators)
+}
+
+// KeywordsSplit returns the entries of every name="keywords" tag, split on any
+// of the characters in seps. Entries are trimmed, empty ones dropped, and
+// duplicates removed case-insensitively, keeping the first spelling in
+// document order.
+func KeywordsSplit(tags []MetaTag, seps string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tag.Source != SourceName || !strings.EqualFold(tag.Name, "keywords") {
+			continue
+		}
+		fields := strings.FieldsFunc(tag.Content, func(r rune) bool {
+			return strings.ContainsRune(seps, r)
+		})
+		for _, kw := range fields {
+			kw = strings.TrimSpace(kw)
+			key := strings.ToLower(kw)
+			if kw == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keywords = append(keywords, kw)
+		}
+	}
+	return keywords
+}
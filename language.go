@@ -0,0 +1,48 @@
+package htmlmetadata
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Language returns the document language as a normalized BCP 47 tag, such as
+// "en-US". It reads the <html lang> attribute, then an http-equiv
+// Content-Language tag, then a name="language" tag, taking the first that
+// holds a valid tag. It returns "" if none does.
+func (r *Result) Language() string {
+	candidates := []string{r.Lang}
+	for _, source := range []AttrSource{SourceHTTPEquiv, SourceName} {
+		for _, tag := range r.Tags {
+			if tag.Source != source {
+				continue
+			}
+			if (source == SourceHTTPEquiv && strings.EqualFold(tag.Name, "content-language")) ||
+				(source == SourceName && strings.EqualFold(tag.Name, "language")) {
+				// Content-Language may list several languages
+				first, _, _ := strings.Cut(tag.Content, ",")
+				candidates = append(candidates, first)
+			}
+		}
+	}
+	for _, c := range candidates {
+		if lang := normalizeLanguage(c); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// normalizeLanguage returns the canonical form of a language tag, accepting
+// underscores as separators, or "" if s is not a valid tag.
+func normalizeLanguage(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "-")
+	if s == "" {
+		return ""
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return tag.String()
+}
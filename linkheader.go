@@ -0,0 +1,130 @@
+package htmlmetadata
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// headerLinks returns the links declared by the Link fields of header, as
+// defined by RFC 8288, with FromHeader set. Targets are resolved against
+// pageURL, the link context, rather than any <base> of the document.
+func headerLinks(header http.Header, pageURL string) []LinkTag {
+	values := header.Values("Link")
+	if len(values) == 0 {
+		return nil
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	var links []LinkTag
+	for _, v := range values {
+		for _, link := range parseLinkHeader(v) {
+			link.Href = resolveURL(base, link.Href)
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// parseLinkHeader parses one Link field value such as
+// `<https://example.com/>; rel="canonical", </style.css>; rel=preload`.
+// Links without a rel parameter, which carry no relation, are dropped, as
+// are malformed entries.
+func parseLinkHeader(v string) []LinkTag {
+	var links []LinkTag
+	for {
+		v = strings.TrimLeft(v, " \t,")
+		if v == "" || v[0] != '<' {
+			return links
+		}
+		end := strings.IndexByte(v, '>')
+		if end < 0 {
+			return links
+		}
+		link := LinkTag{Href: strings.TrimSpace(v[1:end]), FromHeader: true}
+		v = v[end+1:]
+
+		// Parameters up to the comma that starts the next link
+		seen := make(map[string]bool)
+		for {
+			v = strings.TrimLeft(v, " \t")
+			if v == "" || v[0] != ';' {
+				break
+			}
+			var name, value string
+			name, value, v = nextLinkParam(v[1:])
+			// Only the first occurrence of a parameter counts
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			switch name {
+			case "rel":
+				link.Rel = value
+			case "type":
+				link.Type = value
+			case "hreflang":
+				link.Hreflang = value
+			case "title":
+				link.Title = value
+			case "sizes":
+				link.Sizes = value
+			case "as":
+				link.As = strings.ToLower(value)
+			case "crossorigin":
+				link.CrossOrigin = crossOriginMode(value)
+			}
+		}
+		if link.Rel != "" {
+			links = append(links, link)
+		}
+		// Skip anything unparsable up to the next link
+		if v != "" && v[0] != ',' {
+			i := strings.IndexByte(v, ',')
+			if i < 0 {
+				return links
+			}
+			v = v[i:]
+		}
+	}
+}
+
+// nextLinkParam parses a `name=value` or `name="quoted value"` parameter at
+// the start of v, returning the lowercased name, the value and the rest of
+// v from the character after it.
+func nextLinkParam(v string) (name, value, rest string) {
+	v = strings.TrimLeft(v, " \t")
+	i := strings.IndexAny(v, "=;,")
+	if i < 0 {
+		return strings.ToLower(strings.TrimSpace(v)), "", ""
+	}
+	name = strings.ToLower(strings.TrimSpace(v[:i]))
+	if v[i] != '=' {
+		return name, "", v[i:]
+	}
+	v = strings.TrimLeft(v[i+1:], " \t")
+	if v != "" && v[0] == '"' {
+		var sb strings.Builder
+		for j := 1; j < len(v); j++ {
+			switch v[j] {
+			case '\\':
+				if j+1 < len(v) {
+					j++
+					sb.WriteByte(v[j])
+				}
+			case '"':
+				return name, sb.String(), v[j+1:]
+			default:
+				sb.WriteByte(v[j])
+			}
+		}
+		return name, sb.String(), ""
+	}
+	end := strings.IndexAny(v, ";,")
+	if end < 0 {
+		end = len(v)
+	}
+	return name, strings.TrimSpace(v[:end]), v[end:]
+}
@@ -0,0 +1,77 @@
+package htmlmetadata
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// LinkTag represents a single HTML <link> element.
+type LinkTag struct {
+	// Rel is the raw, space-separated rel attribute. Use HasRel to test for a
+	// single relation.
+	Rel string `json:"rel"`
+	// Href is the link target, resolved to an absolute URL when the document
+	// base is known.
+	Href     string `json:"href"`
+	Type     string `json:"type,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Sizes    string `json:"sizes,omitempty"`
+	Title    string `json:"title,omitempty"`
+	// As is the destination of a preload, such as "font" or "script".
+	As string `json:"as,omitempty"`
+	// CrossOrigin is the CORS mode of the request for the target:
+	// "anonymous" or "use-credentials" if a crossorigin attribute is
+	// present, "" if not.
+	CrossOrigin string `json:"crossorigin,omitempty"`
+	// FromHeader reports that the link was declared by a Link HTTP response
+	// header rather than a <link> element.
+	FromHeader bool `json:"from_header,omitempty"`
+}
+
+// HasRel reports whether rel is one of the link's relations. The comparison
+// is case-insensitive.
+func (l LinkTag) HasRel(rel string) bool {
+	for _, r := range strings.Fields(l.Rel) {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// newLinkTag reads the attributes of a <link> element. It returns false if
+// the element has no href.
+func newLinkTag(attrs []html.Attribute) (LinkTag, bool) {
+	var link LinkTag
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "rel":
+			link.Rel = attr.Val
+		case "href":
+			link.Href = strings.TrimSpace(attr.Val)
+		case "type":
+			link.Type = attr.Val
+		case "hreflang":
+			link.Hreflang = attr.Val
+		case "sizes":
+			link.Sizes = attr.Val
+		case "title":
+			link.Title = attr.Val
+		case "as":
+			link.As = strings.ToLower(strings.TrimSpace(attr.Val))
+		case "crossorigin":
+			link.CrossOrigin = crossOriginMode(attr.Val)
+		}
+	}
+	return link, link.Href != ""
+}
+
+// crossOriginMode returns the CORS mode a crossorigin attribute with value v
+// selects. An empty or unknown value means "anonymous".
+func crossOriginMode(v string) string {
+	if strings.EqualFold(strings.TrimSpace(v), "use-credentials") {
+		return "use-credentials"
+	}
+	return "anonymous"
+}
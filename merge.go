@@ -0,0 +1,63 @@
+package htmlmetadata
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// MergeCanonical fills gaps in res with metadata from the page it links to:
+// for an AMP page its rel="canonical" target, whose metadata is often
+// richer, and for any other page its AMP version. It makes at most one
+// request, and none if there is no link or the link points back at res.URL
+// or a URL res was redirected through, so pages that link to each other
+// cannot cause a loop.
+//
+// res is not modified. The returned Result is a copy in which res's own
+// values always win: Title, Lang, JSONLD, Items and RDFa are taken from the
+// linked page only where res has none, and its meta tags are appended only
+// for names res does not have, compared case-insensitively.
+func (e *Extractor) MergeCanonical(ctx context.Context, res *Result) (*Result, error) {
+	merged := *res
+	target := res.AMPURL
+	if res.IsAMP {
+		target = ""
+		for _, link := range res.Links {
+			if link.HasRel("canonical") {
+				target = link.Href
+				break
+			}
+		}
+	}
+	if target == "" || target == res.URL || slices.Contains(res.RedirectChain, target) {
+		return &merged, nil
+	}
+
+	linked, err := e.ExtractResponseContext(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	setFirst(&merged.Title, linked.Title)
+	setFirst(&merged.Lang, linked.Lang)
+	if len(merged.JSONLD) == 0 {
+		merged.JSONLDRaw, merged.JSONLD = linked.JSONLDRaw, linked.JSONLD
+	}
+	if len(merged.Items) == 0 {
+		merged.Items = linked.Items
+	}
+	if len(merged.RDFa) == 0 {
+		merged.RDFa = linked.RDFa
+	}
+
+	have := make(map[string]bool, len(res.Tags))
+	for _, tag := range res.Tags {
+		have[strings.ToLower(tag.Name)] = true
+	}
+	merged.Tags = slices.Clip(merged.Tags)
+	for _, tag := range linked.Tags {
+		if !have[strings.ToLower(tag.Name)] {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	return &merged, nil
+}
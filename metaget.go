@@ -1,93 +1,658 @@
 // Package metadata provides functionality to extract meta tags from web pages.
 // It is designed to be used in web crawlers, scrapers, and other tools that need to extract metadata from HTML pages.
+//
+// # Ordering
+//
+// Extraction is deterministic. Meta tags, links and the lists derived from
+// them, such as OpenGraph images or feeds, are in document order: the order
+// in which their elements start in the source, the content of a <noscript>
+// taking the place of the element. This holds whether the document is
+// parsed into a DOM, as by Parse, or scanned with a tokenizer, as by
+// ParseHead, WithScanFullDocument(false) and ExtractFunc, and for XHTML. The
+// one exception is markup the HTML parser relocates while building the DOM,
+// such as a <meta> inside a <table> but outside its cells, which is moved
+// before the table; the tokenizer keeps it in place. Where duplicates are
+// removed, as by Dedup, the first occurrence is kept. Functions that fetch
+// several pages document the order of their results.
+//
+// # Memory use
+//
+// Bodies are streamed from the network into the parser. Detecting the
+// character encoding looks at the first 1024 bytes only, the window of the
+// HTML encoding sniffing algorithm, and the rest is transcoded as it is
+// read, so a body is only buffered whole when it must outlive the parse:
+// for a Cache, for WithRetainBody, or to reparse an XHTML document that is
+// not well-formed XML. The DOM built by Parse and the default extraction
+// still grows with the document; ParseHead, WithScanFullDocument(false) and
+// ExtractFunc build none, and keep memory use bounded for HTML however large
+// the page.
 package htmlmetadata
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html"
+	"golang.org/x/text/encoding"
+	"golang.org/x/time/rate"
 )
 
+// AttrSource identifies the attribute a MetaTag's Name was read from.
+type AttrSource int
+
+const (
+	// SourceName means Name came from the name attribute.
+	SourceName AttrSource = iota
+	// SourceProperty means Name came from the property attribute (OpenGraph, RDFa).
+	SourceProperty
+	// SourceHTTPEquiv means Name came from the http-equiv attribute, a pragma
+	// directive standing in for an HTTP header.
+	SourceHTTPEquiv
+	// SourceItemProp means Name came from the itemprop attribute of a
+	// microdata <meta>, which WithItemPropTags includes.
+	SourceItemProp
+)
+
+// String returns the attribute name for s.
+func (s AttrSource) String() string {
+	switch s {
+	case SourceName:
+		return "name"
+	case SourceProperty:
+		return "property"
+	case SourceHTTPEquiv:
+		return "http-equiv"
+	case SourceItemProp:
+		return "itemprop"
+	default:
+		return "unknown"
+	}
+}
+
 // MetaTag represents a single HTML meta tag with name and content attributes.
+// By default tags with an empty or missing content attribute are skipped; see
+// WithKeepEmptyContent.
+// Name holds the name attribute when present, then the property attribute,
+// then the http-equiv attribute, then, with WithItemPropTags, the itemprop
+// attribute; Source records which one it was. For a tag with both name and
+// property, WithPreferProperty puts property first.
 type MetaTag struct {
-	Name    string
-	Content string
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	// Property is the raw property attribute, set even when Name came from name.
+	Property string `json:"property,omitempty"`
+	// NameAttr is the raw name attribute, set even when Name came from
+	// property.
+	NameAttr string `json:"name_attr,omitempty"`
+	// ItemProp is the raw itemprop attribute of a microdata <meta>, set even
+	// when Name came from another attribute.
+	ItemProp string     `json:"itemprop,omitempty"`
+	Source   AttrSource `json:"source"`
+	// Media is the media attribute, such as "(prefers-color-scheme: dark)",
+	// which distinguishes otherwise identical tags.
+	Media string `json:"media,omitempty"`
+}
+
+// Result holds everything extracted from a single HTML document.
+type Result struct {
+	// URL is the final URL of the document after redirects. It is empty when
+	// the document was not fetched.
+	URL string `json:"url,omitempty"`
+	// RedirectChain lists every URL requested, in order, from the original
+	// URL to URL; it has a single element when there were no redirects. A
+	// final URL that differs from the requested one may indicate cloaking. It
+	// is nil when the document was not fetched or came from the cache. Chains
+	// that were abandoned are reported by a *RedirectError instead.
+	RedirectChain []string `json:"redirect_chain,omitempty"`
+	// StatusCode and ContentType describe the HTTP response, if any.
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	// ETag and LastModified are the response's validators, for use with
+	// ExtractConditional.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// NotModified reports that ExtractConditional's validators matched and
+	// the server answered 304 Not Modified. No page data is extracted then.
+	NotModified bool `json:"not_modified,omitempty"`
+	// FromCache reports that the document was served from the configured
+	// Cache, possibly after the server confirmed it with a 304.
+	FromCache bool `json:"from_cache,omitempty"`
+	// Location is the absolute redirect target of a 3xx response that was not
+	// followed.
+	Location string `json:"location,omitempty"`
+	// Title is the text of the document's first <title> element.
+	Title string `json:"title,omitempty"`
+	// Tags holds the document's meta tags in document order, as described
+	// under Ordering in the package documentation. The content of
+	// URL-valued tags such as og:image is resolved to an absolute URL when the
+	// document base is known.
+	Tags []MetaTag `json:"tags,omitempty"`
+	// Links holds the links declared by Link response headers, marked with
+	// FromHeader, followed by the document's <link> elements in document
+	// order.
+	Links []LinkTag `json:"links,omitempty"`
+	// Charset is the character encoding the document declares, through
+	// <meta charset> or an http-equiv Content-Type tag, as written. It may
+	// differ from the encoding the document was decoded with, for example when
+	// the HTTP header disagrees.
+	Charset string `json:"charset,omitempty"`
+	// Lang is the lang attribute of the root <html> element, as written.
+	Lang string `json:"lang,omitempty"`
+	// IsAMP reports that the document is an AMP page, marked by <html amp> or
+	// <html ⚡>. AMPURL is the absolute target of its rel="amphtml" link, which
+	// canonical pages use to point at their AMP version.
+	IsAMP  bool   `json:"is_amp,omitempty"`
+	AMPURL string `json:"amp_url,omitempty"`
+	// XRobotsTag holds the values of the X-Robots-Tag response headers. See
+	// Result.Robots, which combines them with the robots meta tags.
+	XRobotsTag []string `json:"x_robots_tag,omitempty"`
+	// Refresh is the first <meta http-equiv="refresh"> directive, if any.
+	Refresh *MetaRefresh `json:"refresh,omitempty"`
+	// JSONLDRaw holds the text of each <script type="application/ld+json">
+	// block, and JSONLD the objects decoded from them.
+	JSONLDRaw []string                 `json:"jsonld_raw,omitempty"`
+	JSONLD    []map[string]interface{} `json:"jsonld,omitempty"`
+	// Items holds the top-level microdata items and RDFa the triples expressed
+	// with RDFa Lite. Both are only extracted by full-document parsing, not by
+	// ParseHead.
+	Items []*Item  `json:"items,omitempty"`
+	RDFa  []Triple `json:"rdfa,omitempty"`
+	// Anchors holds the <a href> links of the document in document order,
+	// one per URL. They are only collected with WithAnchors.
+	Anchors []Anchor `json:"anchors,omitempty"`
+	// WordCount is the number of words in the text of the body, skipping
+	// navigation, scripts and similar boilerplate, and ReadingTime the time
+	// it takes to read them. Both are only computed with WithReadingMetrics,
+	// and not by ParseHead.
+	WordCount   int           `json:"word_count,omitempty"`
+	ReadingTime time.Duration `json:"reading_time_ns,omitempty"`
+	// BytesRead is the number of body bytes read from the response, before
+	// any Content-Encoding is undone by this package; when net/http
+	// decompresses gzip transparently, the decompressed bytes are counted.
+	// FetchDuration is the time until the response headers arrived,
+	// including any preflight and retries, and ParseDuration the time spent
+	// parsing. The body streams into the parser, so ParseDuration includes
+	// the time to download it.
+	BytesRead     int64         `json:"bytes_read,omitempty"`
+	FetchDuration time.Duration `json:"fetch_duration_ns,omitempty"`
+	ParseDuration time.Duration `json:"parse_duration_ns,omitempty"`
+	// Truncated reports that the connection closed before the whole body
+	// arrived, short of its Content-Length or final chunk. The data
+	// extracted from the part received may be incomplete, so a crawler may
+	// want to retry; such bodies are not cached.
+	Truncated bool `json:"truncated,omitempty"`
+	// Body is the response body, after any Content-Encoding is undone but
+	// before character decoding, when WithRetainBody is set. It is not part
+	// of the JSON encoding.
+	Body []byte `json:"-"`
+	// Warnings lists non-fatal problems found while extracting, such as a
+	// duplicate canonical link, a meta tag without content or a malformed
+	// JSON-LD block, in the order found. The messages are meant for people
+	// and may change wording between releases. WithMaxTagCount caps them as
+	// it caps Tags.
+	Warnings []string `json:"warnings,omitempty"`
+	// Err records why the document could not be extracted. It is only set by
+	// the batch APIs, which report failures per URL.
+	Err error `json:"-"`
+
+	base            *url.URL
+	rawMeta         [][]html.Attribute
+	rawLimit        int
+	strictCanonical bool
+	// maxWarnings caps Warnings if positive; droppedWarnings counts the
+	// warnings past it.
+	maxWarnings     int
+	droppedWarnings int
+}
+
+// RawMetaTags returns every <meta> element of the document, in document
+// order, as a map of all its attributes. Unlike Tags nothing is interpreted
+// or skipped: values are as written, relative URLs are not resolved and
+// elements without a name, such as <meta charset>, are included. When an
+// attribute repeats, the first value is kept, as browsers do.
+func (r *Result) RawMetaTags() []map[string]string {
+	if r.rawMeta == nil {
+		return nil
+	}
+	tags := make([]map[string]string, len(r.rawMeta))
+	for i, attrs := range r.rawMeta {
+		raw := make(map[string]string, len(attrs))
+		for _, attr := range attrs {
+			if _, dup := raw[attr.Key]; !dup {
+				raw[attr.Key] = truncateUTF8(attr.Val, r.rawLimit)
+			}
+		}
+		tags[i] = raw
+	}
+	return tags
+}
+
+// DuplicatePolicy selects how AsMap treats meta tags that share a name.
+type DuplicatePolicy int
+
+const (
+	// FirstWins keeps the value of the first tag with a given name.
+	FirstWins DuplicatePolicy = iota
+	// LastWins keeps the value of the last tag with a given name.
+	LastWins
+	// CollectAll joins every value for a name with ", " in document order.
+	CollectAll
+)
+
+// AsMap returns the meta tags keyed by MetaTag.Name, resolving duplicate names
+// according to policy. When a single tag carries both name and property
+// attributes, the name attribute is the key. Names are compared exactly; use
+// WithNormalizeNames to key differently cased names together.
+func (r *Result) AsMap(policy DuplicatePolicy) map[string]string {
+	m := make(map[string]string, len(r.Tags))
+	for _, tag := range r.Tags {
+		prev, ok := m[tag.Name]
+		switch {
+		case !ok:
+			m[tag.Name] = tag.Content
+		case policy == LastWins:
+			m[tag.Name] = tag.Content
+		case policy == CollectAll:
+			m[tag.Name] = prev + ", " + tag.Content
+		}
+	}
+	return m
+}
+
+// Get returns the content of the first meta tag whose name or property
+// attribute equals name, compared case-insensitively, and whether there is
+// one. http-equiv tags are not considered.
+func (r *Result) Get(name string) (string, bool) {
+	for _, tag := range r.Tags {
+		if tag.Source == SourceHTTPEquiv {
+			continue
+		}
+		if strings.EqualFold(tag.Name, name) || strings.EqualFold(tag.Property, name) {
+			return tag.Content, true
+		}
+	}
+	return "", false
+}
+
+// HasTag reports whether the page has a meta tag whose name or property
+// attribute equals name, compared case-insensitively, as Get looks it up.
+func (r *Result) HasTag(name string) bool {
+	_, ok := r.Get(name)
+	return ok
+}
+
+// HasNamespace reports whether the page has a meta tag in the namespace
+// prefix, such as "og" for og:title or "twitter" for twitter:card, going by
+// its name or property attribute and compared case-insensitively.
+func (r *Result) HasNamespace(prefix string) bool {
+	prefix += ":"
+	for _, tag := range r.Tags {
+		if tag.hasPrefix(prefix) || hasPrefixFold(tag.Property, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of meta tags extracted.
+func (r *Result) Count() int {
+	return len(r.Tags)
 }
 
 // Extractor handles the retrieval and parsing of meta tags from web pages.
 type Extractor struct {
-	client *http.Client
+	client      *http.Client
+	transport   http.RoundTripper
+	timeout     time.Duration
+	userAgent   string
+	accept      string
+	referer     string
+	origin      string
+	header      http.Header
+	maxBodySize int64
+
+	defaultCharset encoding.Encoding
+
+	noRedirects  bool
+	sameHost     bool
+	maxRedirects int
+	acceptStatus func(int) bool
+	contentTypes []string
+	forceHTML    bool
+	schemes      []string
+
+	keepEmptyContent bool
+	itemProps        bool
+	preferProperty   bool
+	normalizeNames   bool
+	nameFilter       func(name string) bool
+	dedup            bool
+	maxTagCount      int
+	maxContentLength int
+	canonicalize     bool
+	strictCanonical  bool
+	stripParams      []string
+
+	trimContent        bool
+	collapseWhitespace bool
+	preflightHead      bool
+
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	retryStatus []int
+
+	wordsPerMinute int
+	anchors        bool
+	headOnly       bool
+	retainBody     bool
+
+	cache    Cache
+	hooks    Hooks
+	visitors []TagVisitor
+
+	jar            http.CookieJar
+	initialCookies []initialCookies
+
+	maxConnsPerHost    int
+	resolver           *net.Resolver
+	proxyURL           string
+	tlsConfig          *tls.Config
+	insecureSkipVerify bool
+	i2pTransport       http.RoundTripper
+	rateLimit          *hostLimiter
+	robots             *robotsPolicy
+	hostCacheSize      int
+	wrappers           []func(http.RoundTripper) http.RoundTripper
+
+	httpClient     *http.Client
+	clientWarnings []string
 }
 
-// NewExtractor creates a new Extractor instance with a configurable transport.
-// If transport is nil, http.DefaultTransport will be used.
-func NewExtractor(transport http.RoundTripper) *Extractor {
-	client := &http.Client{
-		Transport: transport,
+// DefaultUserAgent is the User-Agent sent unless WithUserAgent overrides it.
+const DefaultUserAgent = "go-html-metadata/1.0"
+
+// DefaultAccept is the Accept header sent unless WithAccept overrides it.
+const DefaultAccept = "text/html,application/xhtml+xml"
+
+// DefaultContentTypes are the media types parsed unless WithContentTypes
+// overrides them.
+var DefaultContentTypes = []string{"text/html", "application/xhtml+xml"}
+
+// DefaultSchemes are the URL schemes fetched unless WithAllowedSchemes
+// overrides them.
+var DefaultSchemes = []string{"http", "https"}
+
+// New creates a new Extractor configured by opts. Without options it uses
+// http.DefaultTransport, no timeout, DefaultUserAgent and DefaultMaxBodySize.
+func New(opts ...Option) *Extractor {
+	e := &Extractor{
+		userAgent:     DefaultUserAgent,
+		accept:        DefaultAccept,
+		maxBodySize:   DefaultMaxBodySize,
+		maxRedirects:  DefaultMaxRedirects,
+		contentTypes:  DefaultContentTypes,
+		schemes:       DefaultSchemes,
+		maxAttempts:   1,
+		retryStatus:   DefaultRetryStatus,
+		hostCacheSize: DefaultHostCacheSize,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.robots != nil {
+		e.robots.hosts = newHostCache[*robotsEntry](e.hostCacheSize)
+	}
+	if e.rateLimit != nil {
+		e.rateLimit.hosts = newHostCache[*rate.Limiter](e.hostCacheSize)
+	}
+	if e.httpClient != nil {
+		e.client = e.httpClient
+		e.clientWarnings = e.ignoredClientOptions()
+		return e
+	}
+	ownTransport := e.transport == nil
+	if ownTransport {
+		e.transport = http.DefaultTransport
+	}
+	if e.proxyURL != "" || e.tlsConfig != nil || e.insecureSkipVerify || (ownTransport && (e.maxConnsPerHost > 0 || e.resolver != nil)) {
+		e.transport = e.configureTransport(e.transport, ownTransport)
 	}
-	if transport == nil {
-		client.Transport = http.DefaultTransport
+	if e.i2pTransport != nil {
+		e.transport = &i2pRouter{i2p: e.i2pTransport, clearnet: e.transport}
 	}
-	return &Extractor{client: client}
+	if e.rateLimit != nil {
+		e.transport = &rateLimitTransport{limiter: e.rateLimit, next: e.transport}
+	}
+	e.transport = &dataTransport{next: e.transport}
+	for _, wrap := range e.wrappers {
+		e.transport = wrap(e.transport)
+	}
+	if len(e.initialCookies) > 0 {
+		if e.jar == nil {
+			// cookiejar.New only fails for invalid Options
+			e.jar, _ = cookiejar.New(nil)
+		}
+		for _, ic := range e.initialCookies {
+			e.jar.SetCookies(ic.url, ic.cookies)
+		}
+	}
+	e.client = &http.Client{
+		Transport:     e.transport,
+		Timeout:       e.timeout,
+		CheckRedirect: e.checkRedirect,
+		Jar:           e.jar,
+	}
+	return e
 }
 
-// Extract fetches the page at the given URL and extracts all meta tags.
-func (e *Extractor) Extract(url string) ([]MetaTag, error) {
-	// Validate URL
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		return nil, fmt.Errorf("invalid URL scheme: %s", url)
+// ignoredClientOptions returns a warning for each option that configures the
+// http.Client or its transport, which WithHTTPClient overrides.
+func (e *Extractor) ignoredClientOptions() []string {
+	var names []string
+	add := func(set bool, name string) {
+		if set {
+			names = append(names, name)
+		}
+	}
+	add(e.transport != nil, "WithTransport")
+	add(e.timeout != 0, "WithTimeout")
+	add(e.noRedirects || e.maxRedirects != DefaultMaxRedirects || e.sameHost, "the redirect policy")
+	add(e.jar != nil || len(e.initialCookies) > 0, "WithCookieJar and WithCookies")
+	add(e.proxyURL != "", "WithProxy")
+	add(e.tlsConfig != nil || e.insecureSkipVerify, "WithTLSConfig and WithInsecureSkipVerify")
+	add(e.i2pTransport != nil, "WithI2P")
+	add(e.rateLimit != nil, "WithRateLimit")
+	add(e.maxConnsPerHost > 0, "WithMaxConnsPerHost")
+	add(e.resolver != nil, "WithResolver")
+	add(len(e.wrappers) > 0, "WithRoundTripWrapper")
+
+	warnings := make([]string, len(names))
+	for i, name := range names {
+		warnings[i] = name + " ignored: WithHTTPClient is set"
+	}
+	return warnings
+}
+
+// followsRedirects reports whether the redirect policy follows redirects at all.
+func (e *Extractor) followsRedirects() bool {
+	return !e.noRedirects && e.maxRedirects > 0
+}
+
+// stoppedAtRedirect reports whether a response with status code is a
+// redirect the policy chose not to follow, to be returned with its Location.
+func (e *Extractor) stoppedAtRedirect(code int) bool {
+	// With sameHost, the client only hands back redirects that
+	// checkRedirect refused
+	return isRedirect(code) && (!e.followsRedirects() || e.sameHost)
+}
+
+// checkRedirect applies the redirect policy to the next hop.
+func (e *Extractor) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !e.followsRedirects() {
+		return http.ErrUseLastResponse
+	}
+	if err := e.checkRobots(req.Context(), req.URL); err != nil {
+		return err
+	}
+	// net/http keeps an explicit Referer even when leaving https for http
+	if req.URL.Scheme == "http" && via[len(via)-1].URL.Scheme == "https" {
+		req.Header.Del("Referer")
+	}
+	if first := via[0].URL; req.URL.Scheme != first.Scheme || req.URL.Host != first.Host {
+		req.Header.Del("Origin")
+	}
+	if e.sameHost && !strings.EqualFold(req.URL.Hostname(), via[0].URL.Hostname()) {
+		return http.ErrUseLastResponse
 	}
+	if len(via) > e.maxRedirects {
+		// Redirecting to an earlier URL is not a loop in itself, since a page
+		// may set a cookie and redirect to itself, so loops are only reported
+		// once the limit is hit
+		rerr := &RedirectError{}
+		seen := make(map[string]bool)
+		for _, r := range append(via, req) {
+			u := r.URL.String()
+			rerr.Loop = rerr.Loop || seen[u]
+			seen[u] = true
+			rerr.Chain = append(rerr.Chain, u)
+		}
+		return rerr
+	}
+	return nil
+}
+
+// NewExtractor creates a new Extractor instance with a configurable transport.
+// If transport is nil, http.DefaultTransport will be used.
+//
+// Deprecated: Use New with WithTransport.
+func NewExtractor(transport http.RoundTripper, opts ...Option) *Extractor {
+	return New(append([]Option{WithTransport(transport)}, opts...)...)
+}
 
-	// Fetch the page
-	resp, err := e.client.Get(url)
+// Extract fetches the page at the given URL and extracts all meta tags.
+func (e *Extractor) Extract(rawURL string) ([]MetaTag, error) {
+	return e.ExtractContext(context.Background(), rawURL)
+}
+
+// ExtractContext is like Extract but carries ctx on the outbound request, so the
+// fetch can be canceled or bounded by a deadline. The deadline also covers
+// parsing: if it passes while a slow document is being parsed, ExtractContext
+// returns at once with an error wrapping ErrTimeout.
+func (e *Extractor) ExtractContext(ctx context.Context, rawURL string) ([]MetaTag, error) {
+	res, err := e.ExtractResponseContext(ctx, rawURL)
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) && res != nil {
+		// The tags found before the panic
+		return res.Tags, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return res.Tags, nil
+}
+
+// ExtractResponse fetches the page at the given URL and returns its meta tags
+// together with details of the response. If the server answers with a status
+// other than 200, the returned error is accompanied by a Result describing the
+// response, without tags.
+func (e *Extractor) ExtractResponse(rawURL string) (*Result, error) {
+	return e.ExtractResponseContext(context.Background(), rawURL)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// Parse parses already-fetched HTML from r and returns the title and meta tags.
+// It always parses the whole document, regardless of WithScanFullDocument.
+// Documents starting with an XML declaration or an XHTML doctype are parsed
+// as XML, falling back to HTML if they are not well-formed; fetched
+// documents are parsed as XML when served as application/xhtml+xml.
+func (e *Extractor) Parse(r io.Reader) (*Result, error) {
+	return e.parse(r, "", "", nil)
+}
+
+// ParseHead is like Parse but scans the document with a tokenizer and stops at
+// the end of <head>, without building a DOM. This is much cheaper on large
+// pages, but misses anything placed after the head and is less forgiving of
+// unusual markup than Parse. The tags it finds come in the same order as
+// from Parse.
+func (e *Extractor) ParseHead(r io.Reader) (*Result, error) {
+	return e.parseHead(r, "", "", nil)
+}
+
+// ExtractFromReader parses already-fetched HTML from r and extracts all meta tags.
+// No network request is made.
+func (e *Extractor) ExtractFromReader(r io.Reader) ([]MetaTag, error) {
+	return e.extractMetaTags(r)
+}
+
+// ExtractFragment parses r as an HTML fragment, such as an email body or a
+// snippet from a CMS, and extracts all meta tags. No network request is made.
+//
+// ExtractFromReader parses a whole document, and the parser builds the
+// implied <html>, <head> and <body> around partial markup, moving elements
+// it considers misplaced. ExtractFragment instead parses r as the content of
+// a <body> element, as html.ParseFragment does: elements stay where they
+// appear, and <html>, <head> and <body> tags within r are ignored. A bare
+// <meta name="description" content="..."> yields the same tag either way.
+func (e *Extractor) ExtractFragment(r io.Reader) ([]MetaTag, error) {
+	res, err := e.parseFragment(r)
+	if res == nil {
+		return nil, err
 	}
+	// After a PanicError, res holds what was found before the panic
+	return res.Tags, err
+}
 
-	// Parse the HTML
-	return e.extractMetaTags(resp.Body)
+// ExtractFunc parses r and calls fn with each meta tag, in document order, as
+// soon as it is read. Returning false from fn stops the parse: nothing more
+// of r is read and fn is not called again. This suits looking for one tag in
+// large documents or streams, where collecting every tag is wasted work.
+//
+// The document is scanned with a tokenizer, as by ParseHead but to its end,
+// so no DOM is built. Options that shape a single tag apply as usual;
+// WithDedup and WithMaxTagCount, which act on the collected list, do not.
+// Relative URLs are resolved only against a <base href> read before the tag.
+func (e *Extractor) ExtractFunc(r io.Reader, fn func(MetaTag) bool) error {
+	return e.parseEach(r, fn)
 }
 
-// extractMetaTags parses HTML content and extracts meta tags.
-func (e *Extractor) extractMetaTags(r io.Reader) ([]MetaTag, error) {
-	doc, err := html.Parse(r)
+// ExtractFromBytes parses the HTML document in data and extracts all meta
+// tags. No network request is made. See WithDefaultCharset for documents that
+// do not declare their encoding.
+func (e *Extractor) ExtractFromBytes(data []byte) ([]MetaTag, error) {
+	return e.extractMetaTags(bytes.NewReader(data))
+}
+
+// ExtractFromString parses the given HTML document and extracts all meta tags.
+func (e *Extractor) ExtractFromString(s string) ([]MetaTag, error) {
+	return e.extractMetaTags(strings.NewReader(s))
+}
+
+// ExtractFromFile parses the HTML file at path and extracts all meta tags.
+func (e *Extractor) ExtractFromFile(path string) ([]MetaTag, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
-	}
-
-	var tags []MetaTag
-	var traverse func(*html.Node)
-	traverse = func(n *html.Node) {
-		if n.Type == html.ElementNode && n.Data == "meta" {
-			var name, content string
-			for _, attr := range n.Attr {
-				switch attr.Key {
-				case "name", "property":
-					name = attr.Val
-				case "content":
-					content = attr.Val
-				}
-			}
-			if name != "" && content != "" {
-				tags = append(tags, MetaTag{
-					Name:    name,
-					Content: content,
-				})
-			}
-		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			traverse(c)
-		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	traverse(doc)
+	defer f.Close()
+	return e.extractMetaTags(f)
+}
 
-	return tags, nil
+// extractMetaTags parses HTML content and extracts meta tags.
+func (e *Extractor) extractMetaTags(r io.Reader) ([]MetaTag, error) {
+	res, err := e.scan(r, "", "", nil)
+	if res == nil {
+		return nil, err
+	}
+	// After a PanicError, res holds what was found before the panic
+	return res.Tags, err
 }
@@ -0,0 +1,174 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// benchmarkDocument returns a page with a typical head and a body of nested
+// markup, for benchmarks.
+func benchmarkDocument() []byte {
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html><html lang="en"><head><meta charset="utf-8"><title>Benchmark</title>`)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, `<meta property="og:tag%d" content="value %d">`, i, i)
+	}
+	b.WriteString(`<link rel="canonical" href="https://example.com/"></head><body>`)
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, `<div class="c%d"><p>Paragraph <a href="/p%d">link</a> <b>bold</b></p></div>`, i, i)
+	}
+	b.WriteString(`</body></html>`)
+	return []byte(b.String())
+}
+
+func BenchmarkExtractFromReader(b *testing.B) {
+	doc := benchmarkDocument()
+	e := New()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(doc)))
+	for i := 0; i < b.N; i++ {
+		if _, err := e.ExtractFromReader(bytes.NewReader(doc)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// redirectServer serves /page, redirects /loop to itself, and redirects
+// /hop/N to /hop/N-1, with /hop/0 going to /page.
+func redirectServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch n, hop := strings.CutPrefix(r.URL.Path, "/hop/"); {
+		case r.URL.Path == "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case hop && n == "0":
+			http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+		case hop:
+			i, _ := strconv.Atoi(n)
+			http.Redirect(w, r, "/hop/"+strconv.Itoa(i-1), http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<title>Landed</title>"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRedirectChain(t *testing.T) {
+	srv, _ := redirectServer(t)
+	res, err := New().ExtractResponse(srv.URL + "/hop/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{srv.URL + "/hop/1", srv.URL + "/hop/0", srv.URL + "/page"}
+	if !slices.Equal(res.RedirectChain, want) || res.URL != want[2] || res.Title != "Landed" {
+		t.Errorf("URL = %q, Title = %q, RedirectChain = %q, want %q", res.URL, res.Title, res.RedirectChain, want)
+	}
+}
+
+func TestRedirectsNotFollowed(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"WithFollowRedirects(false)": WithFollowRedirects(false),
+		"WithMaxRedirects(0)":        WithMaxRedirects(0),
+	} {
+		srv, requests := redirectServer(t)
+		res, err := New(opt).ExtractResponse(srv.URL + "/hop/0")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if res.StatusCode != http.StatusMovedPermanently || res.Location != srv.URL+"/page" || res.Title != "" {
+			t.Errorf("%s: StatusCode = %d, Location = %q, Title = %q", name, res.StatusCode, res.Location, res.Title)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("%s: %d requests, want 1", name, got)
+		}
+	}
+}
+
+func TestTooManyRedirects(t *testing.T) {
+	srv, _ := redirectServer(t)
+	tests := []struct {
+		name, path string
+		opts       []Option
+		chain      int
+		loop       bool
+	}{
+		{"default limit", "/hop/20", nil, DefaultMaxRedirects + 2, false},
+		{"custom limit", "/hop/5", []Option{WithMaxRedirects(3)}, 5, false},
+		{"within the limit", "/hop/2", []Option{WithMaxRedirects(3)}, 0, false},
+		{"loop", "/loop", []Option{WithMaxRedirects(3)}, 5, true},
+	}
+	for _, tt := range tests {
+		_, err := New(tt.opts...).ExtractResponse(srv.URL + tt.path)
+		if tt.chain == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		var rerr *RedirectError
+		if !errors.As(err, &rerr) || !errors.Is(err, ErrTooManyRedirects) {
+			t.Errorf("%s: err = %v, want a RedirectError", tt.name, err)
+			continue
+		}
+		if len(rerr.Chain) != tt.chain || rerr.Loop != tt.loop {
+			t.Errorf("%s: Chain = %q, Loop = %v, want %d URLs", tt.name, rerr.Chain, rerr.Loop, tt.chain)
+		}
+		if rerr.Chain[0] != srv.URL+tt.path {
+			t.Errorf("%s: chain starts at %q", tt.name, rerr.Chain[0])
+		}
+	}
+}
+
+func TestSameHostRedirectsOnly(t *testing.T) {
+	var offHost atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offHost.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Elsewhere</title>"))
+	}))
+	defer other.Close()
+	// Served from 127.0.0.1, so localhost is another host
+	target := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, "/next", http.StatusFound)
+		case "/next":
+			http.Redirect(w, r, target+"/", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	res, err := New(WithSameHostRedirectsOnly(true)).ExtractResponse(srv.URL + "/start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusFound || res.Location != target+"/" {
+		t.Errorf("StatusCode = %d, Location = %q, want the off-host redirect returned", res.StatusCode, res.Location)
+	}
+	if want := []string{srv.URL + "/start", srv.URL + "/next"}; !slices.Equal(res.RedirectChain, want) {
+		t.Errorf("RedirectChain = %q, want %q", res.RedirectChain, want)
+	}
+	if offHost.Load() != 0 {
+		t.Error("the off-host target was requested")
+	}
+
+	res, err = New().ExtractResponse(srv.URL + "/start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Title != "Elsewhere" || offHost.Load() != 1 {
+		t.Errorf("without the option: Title = %q", res.Title)
+	}
+}
@@ -0,0 +1,162 @@
+package htmlmetadata
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Item is an HTML microdata item, an element carrying itemscope.
+type Item struct {
+	// Type lists the item types from itemtype, e.g. https://schema.org/Product.
+	// It is a slice because itemtype is a set of space-separated tokens, and
+	// pages give several types to one item, as in "Product Offer".
+	Type []string `json:"type,omitempty"`
+	// ID is the global identifier from itemid, if any.
+	ID string `json:"id,omitempty"`
+	// Properties maps each property name to its values in document order.
+	// A value is a string, or an *Item for nested items.
+	Properties map[string][]interface{} `json:"properties,omitempty"`
+}
+
+// extractMicrodata returns the top-level microdata items of doc following
+// the WHATWG microdata algorithm. URL-valued properties are resolved against
+// base.
+func extractMicrodata(doc *html.Node, base *url.URL) []*Item {
+	var roots []*html.Node
+	ids := make(map[string]*html.Node)
+	order := make(map[*html.Node]int)
+	stack := []*html.Node{doc}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = pushChildren(stack[:len(stack)-1], n)
+		if n.Type != html.ElementNode {
+			continue
+		}
+		order[n] = len(order)
+		if id := attrValue(n.Attr, "id"); id != "" {
+			if _, ok := ids[id]; !ok {
+				ids[id] = n
+			}
+		}
+		if hasAttr(n, "itemscope") && !hasAttr(n, "itemprop") {
+			roots = append(roots, n)
+		}
+	}
+
+	md := &microdata{ids: ids, order: order, base: base, active: make(map[*html.Node]bool)}
+	var items []*Item
+	for _, root := range roots {
+		items = append(items, md.item(root))
+	}
+	return items
+}
+
+// microdata holds the state of one extraction.
+type microdata struct {
+	ids map[string]*html.Node
+	// order holds the position of each element in tree order.
+	order map[*html.Node]int
+	base  *url.URL
+	// active holds the items being built, to break itemref cycles.
+	active map[*html.Node]bool
+}
+
+// item builds the Item rooted at n.
+func (md *microdata) item(n *html.Node) *Item {
+	md.active[n] = true
+	defer delete(md.active, n)
+
+	it := &Item{
+		Type:       strings.Fields(attrValue(n.Attr, "itemtype")),
+		ID:         strings.TrimSpace(attrValue(n.Attr, "itemid")),
+		Properties: make(map[string][]interface{}),
+	}
+
+	// Crawl the children and any elements named by itemref
+	var pending []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		pending = append(pending, child)
+	}
+	for _, id := range strings.Fields(attrValue(n.Attr, "itemref")) {
+		if ref, ok := md.ids[id]; ok {
+			pending = append(pending, ref)
+		}
+	}
+
+	var props []*html.Node
+	visited := make(map[*html.Node]bool)
+	for len(pending) > 0 {
+		el := pending[0]
+		pending = pending[1:]
+		if el.Type != html.ElementNode || visited[el] {
+			continue
+		}
+		visited[el] = true
+
+		if strings.TrimSpace(attrValue(el.Attr, "itemprop")) != "" {
+			props = append(props, el)
+		}
+		// A nested item owns its own subtree
+		if !hasAttr(el, "itemscope") {
+			for child := el.FirstChild; child != nil; child = child.NextSibling {
+				pending = append(pending, child)
+			}
+		}
+	}
+
+	// The crawl is breadth-first and itemref targets may precede the item;
+	// values are recorded in tree order, as the algorithm requires
+	slices.SortFunc(props, func(a, b *html.Node) int {
+		return cmp.Compare(md.order[a], md.order[b])
+	})
+	for _, el := range props {
+		value := md.value(el)
+		for _, name := range strings.Fields(attrValue(el.Attr, "itemprop")) {
+			it.Properties[name] = append(it.Properties[name], value)
+		}
+	}
+	return it
+}
+
+// value returns the property value of el.
+func (md *microdata) value(el *html.Node) interface{} {
+	if hasAttr(el, "itemscope") {
+		if md.active[el] {
+			// Cyclic reference; report the item by its identifier
+			return attrValue(el.Attr, "itemid")
+		}
+		return md.item(el)
+	}
+	switch el.DataAtom {
+	case atom.Meta:
+		return attrValue(el.Attr, "content")
+	case atom.Audio, atom.Embed, atom.Iframe, atom.Img, atom.Source, atom.Track, atom.Video:
+		return resolveURL(md.base, strings.TrimSpace(attrValue(el.Attr, "src")))
+	case atom.A, atom.Area, atom.Link:
+		return resolveURL(md.base, strings.TrimSpace(attrValue(el.Attr, "href")))
+	case atom.Object:
+		return resolveURL(md.base, strings.TrimSpace(attrValue(el.Attr, "data")))
+	case atom.Data, atom.Meter:
+		return attrValue(el.Attr, "value")
+	case atom.Time:
+		if hasAttr(el, "datetime") {
+			return attrValue(el.Attr, "datetime")
+		}
+	}
+	return textContent(el)
+}
+
+// hasAttr reports whether n carries the attribute key.
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package htmlmetadata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMicrodataTreeOrder(t *testing.T) {
+	const doc = `<html><body>
+<p id="before" itemprop="a">zero</p>
+<div itemscope itemref="before after">
+<div><span itemprop="a">first</span></div>
+<span itemprop="a">second</span>
+<div itemprop="a" itemscope><span itemprop="b">nested</span></div>
+</div>
+<p id="after"><span itemprop="a">last</span></p>
+</body></html>`
+	items := parseString(t, doc).Items
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	got := items[0].Properties["a"]
+	if len(got) != 5 {
+		t.Fatalf("a = %v, want 5 values", got)
+	}
+	for i, want := range []string{"zero", "first", "second", "", "last"} {
+		if want != "" && got[i] != want {
+			t.Errorf("a[%d] = %v, want %q", i, got[i], want)
+		}
+	}
+	if nested, ok := got[3].(*Item); !ok || !reflect.DeepEqual(nested.Properties["b"], []interface{}{"nested"}) {
+		t.Errorf("a[3] = %v, want the nested item", got[3])
+	}
+	if _, ok := items[0].Properties["b"]; ok {
+		t.Error("the nested item's property leaked into its parent")
+	}
+}
+
+func TestMicrodataProduct(t *testing.T) {
+	const doc = `<html><head><base href="https://shop.example/items/"></head><body>
+<div itemscope itemtype="https://schema.org/Product https://schema.org/Thing" itemid="urn:sku:42">
+<h1 itemprop="name">  Anvil  </h1>
+<img itemprop="image" src="anvil.jpg">
+<a itemprop="url" href="/anvil">Details</a>
+<meta itemprop="sku" content="42">
+<time itemprop="releaseDate" datetime="2024-05-01">May 1st</time>
+<time itemprop="note">someday</time>
+<data itemprop="weight" value="50">fifty</data>
+<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+<span itemprop="price">19.99</span>
+<link itemprop="availability" href="https://schema.org/InStock">
+</div>
+<span itemprop="review" itemref="rating" itemscope></span>
+</div>
+<div id="rating"><span itemprop="ratingValue">4</span></div>
+<div itemscope><span id="loop" itemprop="self" itemscope itemid="urn:loop" itemref="loop"></span></div>
+</body></html>`
+	items := parseString(t, doc).Items
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	product := items[0]
+	if want := []string{"https://schema.org/Product", "https://schema.org/Thing"}; !reflect.DeepEqual(product.Type, want) {
+		t.Errorf("Type = %q, want %q", product.Type, want)
+	}
+	if product.ID != "urn:sku:42" {
+		t.Errorf("ID = %q", product.ID)
+	}
+	for name, want := range map[string]string{
+		"name":        "Anvil",
+		"image":       "https://shop.example/items/anvil.jpg",
+		"url":         "https://shop.example/anvil",
+		"sku":         "42",
+		"releaseDate": "2024-05-01",
+		"note":        "someday",
+		"weight":      "50",
+	} {
+		if got := product.Properties[name]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %v, want %q", name, got, want)
+		}
+	}
+	offers, ok := product.Properties["offers"][0].(*Item)
+	if !ok {
+		t.Fatalf("offers = %v, want a nested item", product.Properties["offers"])
+	}
+	want := map[string][]interface{}{
+		"price":        {"19.99"},
+		"availability": {"https://schema.org/InStock"},
+	}
+	if !reflect.DeepEqual(offers.Properties, want) || !reflect.DeepEqual(offers.Type, []string{"https://schema.org/Offer"}) {
+		t.Errorf("offers = %+v", offers)
+	}
+	review, ok := product.Properties["review"][0].(*Item)
+	if !ok || !reflect.DeepEqual(review.Properties["ratingValue"], []interface{}{"4"}) {
+		t.Errorf("review = %v, want ratingValue from the itemref", product.Properties["review"])
+	}
+	if _, ok := product.Properties["ratingValue"]; ok {
+		t.Error("the nested item's itemref leaked into its parent")
+	}
+
+	// An item reaching itself through itemref is cut off at the repeat
+	loop, ok := items[1].Properties["self"][0].(*Item)
+	if !ok || !reflect.DeepEqual(loop.Properties["self"], []interface{}{"urn:loop"}) {
+		t.Errorf("self = %v, want the cycle reported by itemid", items[1].Properties["self"])
+	}
+}
@@ -0,0 +1,54 @@
+package htmlmetadata
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// subresourceRels are the link types whose target is loaded with the page,
+// as opposed to ones such as canonical that merely point somewhere.
+var subresourceRels = []string{
+	"icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon",
+	"stylesheet", "preload", "prefetch", "modulepreload", "manifest", "image_src",
+}
+
+// MixedContent returns the http:// URLs an https page loads through
+// URL-valued meta tags such as og:image and through <link> elements for
+// icons, stylesheets, preloads and the like, without duplicates: the meta
+// tags' first, then the links', each in document order. Browsers block or
+// warn about such resources, and their use in previews exposes the reader to
+// tampering on the network. The page scheme is taken from URL, or from the
+// document base if the page was not fetched. MixedContent returns nil for
+// pages not served over https.
+func (r *Result) MixedContent() []string {
+	page := r.base
+	if u, err := url.Parse(r.URL); err == nil && u.IsAbs() {
+		page = u
+	}
+	if page == nil || !strings.EqualFold(page.Scheme, "https") {
+		return nil
+	}
+
+	var insecure []string
+	add := func(ref string) {
+		u, err := url.Parse(strings.TrimSpace(r.ResolveReference(ref)))
+		if err != nil || !strings.EqualFold(u.Scheme, "http") {
+			return
+		}
+		if s := u.String(); !slices.Contains(insecure, s) {
+			insecure = append(insecure, s)
+		}
+	}
+	for _, tag := range r.Tags {
+		if isURLValued(tag.Name) {
+			add(tag.Content)
+		}
+	}
+	for _, link := range r.Links {
+		if slices.ContainsFunc(subresourceRels, link.HasRel) {
+			add(link.Href)
+		}
+	}
+	return insecure
+}
@@ -0,0 +1,93 @@
+package htmlmetadata
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ThemeColor is a name="theme-color" declaration. Media is the media query it
+// applies to, such as "(prefers-color-scheme: dark)", or "" for all media.
+type ThemeColor struct {
+	Color string `json:"color"`
+	Media string `json:"media,omitempty"`
+}
+
+// ThemeColors returns every theme-color declaration in document order.
+func (r *Result) ThemeColors() []ThemeColor {
+	var colors []ThemeColor
+	for _, tag := range r.Tags {
+		if tag.Source == SourceName && strings.EqualFold(tag.Name, "theme-color") && tag.Content != "" {
+			colors = append(colors, ThemeColor{Color: strings.TrimSpace(tag.Content), Media: tag.Media})
+		}
+	}
+	return colors
+}
+
+// Viewport is a parsed name="viewport" declaration. Numeric fields are zero
+// when unset.
+type Viewport struct {
+	// Width and Height are "device-width"/"device-height" or a pixel count.
+	Width        string  `json:"width,omitempty"`
+	Height       string  `json:"height,omitempty"`
+	InitialScale float64 `json:"initial_scale,omitempty"`
+	MinimumScale float64 `json:"minimum_scale,omitempty"`
+	MaximumScale float64 `json:"maximum_scale,omitempty"`
+	// UserScalable is "yes", "no", or "" when unset.
+	UserScalable string `json:"user_scalable,omitempty"`
+	ViewportFit  string `json:"viewport_fit,omitempty"`
+	// Extra holds any other properties, keyed by lowercase name.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// Viewport parses the first name="viewport" tag. It returns false if there is
+// none.
+func (r *Result) Viewport() (Viewport, bool) {
+	for _, tag := range r.Tags {
+		if tag.Source == SourceName && strings.EqualFold(tag.Name, "viewport") {
+			return parseViewport(tag.Content), true
+		}
+	}
+	return Viewport{}, false
+}
+
+// parseViewport parses viewport content such as
+// "width=device-width, initial-scale=1". Semicolons are accepted as
+// separators too, as browsers do.
+func parseViewport(content string) Viewport {
+	vp := Viewport{Extra: make(map[string]string)}
+	fields := strings.FieldsFunc(content, func(r rune) bool { return r == ',' || r == ';' })
+	for _, field := range fields {
+		key, value, _ := strings.Cut(field, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "":
+		case "width":
+			vp.Width = value
+		case "height":
+			vp.Height = value
+		case "initial-scale":
+			vp.InitialScale = parseScale(value)
+		case "minimum-scale":
+			vp.MinimumScale = parseScale(value)
+		case "maximum-scale":
+			vp.MaximumScale = parseScale(value)
+		case "user-scalable":
+			vp.UserScalable = strings.ToLower(value)
+		case "viewport-fit":
+			vp.ViewportFit = strings.ToLower(value)
+		default:
+			vp.Extra[key] = value
+		}
+	}
+	return vp
+}
+
+// parseScale parses a scale factor, returning 0 if it is not a number.
+func parseScale(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return f
+}
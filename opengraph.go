@@ -0,0 +1,227 @@
+package htmlmetadata
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// OpenGraph holds the common OpenGraph properties of a page.
+type OpenGraph struct {
+	Title       string `json:"title,omitempty"`
+	Type        string `json:"type,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	// Locale is the og:locale of the content, such as "en_US", and
+	// AlternateLocales the other locales it is available in, from each
+	// og:locale:alternate. Both are as written; values that are not valid
+	// language tags are dropped. See LanguageTags for BCP 47 forms.
+	Locale           string   `json:"locale,omitempty"`
+	AlternateLocales []string `json:"alternate_locales,omitempty"`
+	// Images lists every og:image (or og:image:url) in document order.
+	Images []string `json:"images,omitempty"`
+	// ImageDetails holds the same images, in the same order, together with
+	// the structured properties (og:image:width and so on) that follow each.
+	ImageDetails []OpenGraphImage `json:"image_details,omitempty"`
+	// Videos and Audios list every og:video and og:audio (or their :url
+	// forms) in document order, each with the structured properties that
+	// follow it.
+	Videos []OpenGraphVideo `json:"videos,omitempty"`
+	Audios []OpenGraphAudio `json:"audios,omitempty"`
+	// Extra holds og:* properties without a dedicated field, keyed by the full
+	// property name. If a property repeats, the first value is kept.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// OpenGraphImage is an og:image grouped with its structured properties.
+type OpenGraphImage struct {
+	URL       string `json:"url"`
+	SecureURL string `json:"secure_url,omitempty"`
+	Type      string `json:"type,omitempty"`
+	// Width and Height are in pixels, or 0 if not given or not a number.
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Alt    string `json:"alt,omitempty"`
+}
+
+// OpenGraphVideo is an og:video grouped with its structured properties.
+type OpenGraphVideo struct {
+	URL       string `json:"url"`
+	SecureURL string `json:"secure_url,omitempty"`
+	Type      string `json:"type,omitempty"`
+	// Width and Height are in pixels, or 0 if not given or not a number.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// PreferredURL returns SecureURL if it is an https URL, and URL otherwise.
+func (v OpenGraphVideo) PreferredURL() string {
+	return preferSecure(v.URL, v.SecureURL)
+}
+
+// OpenGraphAudio is an og:audio grouped with its structured properties.
+type OpenGraphAudio struct {
+	URL       string `json:"url"`
+	SecureURL string `json:"secure_url,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// PreferredURL returns SecureURL if it is an https URL, and URL otherwise.
+func (a OpenGraphAudio) PreferredURL() string {
+	return preferSecure(a.URL, a.SecureURL)
+}
+
+// preferSecure returns secureURL if it uses https, and rawURL otherwise.
+func preferSecure(rawURL, secureURL string) string {
+	if hasPrefixFold(strings.TrimSpace(secureURL), "https://") {
+		return secureURL
+	}
+	return rawURL
+}
+
+// ParseOpenGraph collects the og:* entries from tags into an OpenGraph.
+//
+// Following the protocol's structured property rules, an og:image:* property
+// describes the most recent og:image before it, so tags must be in document
+// order, as Result.Tags is. Properties that appear before any og:image are
+// ignored for ImageDetails, as is a repeated property within one image. An
+// empty og:image, which only WithKeepEmptyContent retains, adds no image,
+// and the properties that follow it are ignored up to the next og:image.
+// og:video:* and og:audio:* properties are grouped with the most recent
+// og:video and og:audio the same way. The og: name of a tag is taken from
+// its other attribute if Name holds another name.
+func ParseOpenGraph(tags []MetaTag) OpenGraph {
+	og := OpenGraph{Extra: make(map[string]string)}
+	// blankImage, blankVideo and blankAudio are set while the most recent
+	// og:image, og:video or og:audio is empty
+	blankImage, blankVideo, blankAudio := false, false, false
+	for _, tag := range tags {
+		tag.Name = vocabularyName(tag, "og:")
+		if !strings.HasPrefix(tag.Name, "og:") {
+			continue
+		}
+		switch tag.Name {
+		case "og:title":
+			setFirst(&og.Title, tag.Content)
+		case "og:type":
+			setFirst(&og.Type, tag.Content)
+		case "og:url":
+			setFirst(&og.URL, tag.Content)
+		case "og:description":
+			setFirst(&og.Description, tag.Content)
+		case "og:site_name":
+			setFirst(&og.SiteName, tag.Content)
+		case "og:locale":
+			if og.Locale == "" && normalizeLanguage(tag.Content) != "" {
+				og.Locale = strings.TrimSpace(tag.Content)
+			}
+		case "og:locale:alternate":
+			locale := strings.TrimSpace(tag.Content)
+			if normalizeLanguage(locale) != "" && !slices.Contains(og.AlternateLocales, locale) {
+				og.AlternateLocales = append(og.AlternateLocales, locale)
+			}
+		case "og:image", "og:image:url":
+			// Kept by WithKeepEmptyContent, but not an image
+			blankImage = tag.Content == ""
+			if blankImage {
+				continue
+			}
+			og.Images = append(og.Images, tag.Content)
+			og.ImageDetails = append(og.ImageDetails, OpenGraphImage{URL: tag.Content})
+		case "og:video", "og:video:url":
+			if blankVideo = tag.Content == ""; !blankVideo {
+				og.Videos = append(og.Videos, OpenGraphVideo{URL: tag.Content})
+			}
+		case "og:audio", "og:audio:url":
+			if blankAudio = tag.Content == ""; !blankAudio {
+				og.Audios = append(og.Audios, OpenGraphAudio{URL: tag.Content})
+			}
+		default:
+			if n := len(og.ImageDetails); n > 0 && !blankImage {
+				og.ImageDetails[n-1].set(tag.Name, tag.Content)
+			}
+			if n := len(og.Videos); n > 0 && !blankVideo {
+				og.Videos[n-1].set(tag.Name, tag.Content)
+			}
+			if n := len(og.Audios); n > 0 && !blankAudio {
+				og.Audios[n-1].set(tag.Name, tag.Content)
+			}
+			if _, ok := og.Extra[tag.Name]; !ok {
+				og.Extra[tag.Name] = tag.Content
+			}
+		}
+	}
+	return og
+}
+
+// LanguageTags returns Locale followed by AlternateLocales as normalized
+// BCP 47 tags, with hyphens in place of OpenGraph's underscores: "en_US"
+// becomes "en-US". Duplicates are removed.
+func (og OpenGraph) LanguageTags() []string {
+	var tags []string
+	for _, locale := range append([]string{og.Locale}, og.AlternateLocales...) {
+		if tag := normalizeLanguage(locale); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// set applies the structured property name to the image.
+func (img *OpenGraphImage) set(name, content string) {
+	switch name {
+	case "og:image:secure_url":
+		setFirst(&img.SecureURL, content)
+	case "og:image:type":
+		setFirst(&img.Type, content)
+	case "og:image:width":
+		setFirstInt(&img.Width, content)
+	case "og:image:height":
+		setFirstInt(&img.Height, content)
+	case "og:image:alt":
+		setFirst(&img.Alt, content)
+	}
+}
+
+// set applies the structured property name to the video.
+func (v *OpenGraphVideo) set(name, content string) {
+	switch name {
+	case "og:video:secure_url":
+		setFirst(&v.SecureURL, content)
+	case "og:video:type":
+		setFirst(&v.Type, content)
+	case "og:video:width":
+		setFirstInt(&v.Width, content)
+	case "og:video:height":
+		setFirstInt(&v.Height, content)
+	}
+}
+
+// set applies the structured property name to the audio.
+func (a *OpenGraphAudio) set(name, content string) {
+	switch name {
+	case "og:audio:secure_url":
+		setFirst(&a.SecureURL, content)
+	case "og:audio:type":
+		setFirst(&a.Type, content)
+	}
+}
+
+// setFirstInt assigns val, parsed as a positive integer, to dst unless dst
+// already holds a value.
+func setFirstInt(dst *int, val string) {
+	if *dst != 0 {
+		return
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(val)); err == nil && n > 0 {
+		*dst = n
+	}
+}
+
+// setFirst assigns val to dst unless dst already holds a value.
+func setFirst(dst *string, val string) {
+	if *dst == "" {
+		*dst = val
+	}
+}
@@ -0,0 +1,82 @@
+package htmlmetadata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOpenGraphVideoFixtures(t *testing.T) {
+	tests := []struct {
+		name, doc string
+		want      []OpenGraphVideo
+		preferred []string
+		// imageWidth is the og:image:width, which video properties must not set
+		imageWidth int
+	}{
+		{
+			name: "YouTube",
+			doc: `<html><head>
+<meta property="og:site_name" content="YouTube">
+<meta property="og:url" content="https://www.youtube.com/watch?v=dQw4w9WgXcQ">
+<meta property="og:title" content="Example Video">
+<meta property="og:image" content="https://i.ytimg.com/vi/dQw4w9WgXcQ/maxresdefault.jpg">
+<meta property="og:image:width" content="1280">
+<meta property="og:image:height" content="720">
+<meta property="og:type" content="video.other">
+<meta property="og:video:url" content="https://www.youtube.com/embed/dQw4w9WgXcQ">
+<meta property="og:video:secure_url" content="https://www.youtube.com/embed/dQw4w9WgXcQ">
+<meta property="og:video:type" content="text/html">
+<meta property="og:video:width" content="1280">
+<meta property="og:video:height" content="720">
+<meta property="og:video:tag" content="music">
+</head></html>`,
+			want: []OpenGraphVideo{
+				{URL: "https://www.youtube.com/embed/dQw4w9WgXcQ", SecureURL: "https://www.youtube.com/embed/dQw4w9WgXcQ", Type: "text/html", Width: 1280, Height: 720},
+			},
+			preferred:  []string{"https://www.youtube.com/embed/dQw4w9WgXcQ"},
+			imageWidth: 1280,
+		},
+		{
+			name: "Vimeo",
+			doc: `<html><head>
+<meta property="og:site_name" content="Vimeo">
+<meta property="og:url" content="https://vimeo.com/76979871">
+<meta property="og:type" content="video.other">
+<meta property="og:title" content="Example Film">
+<meta property="og:image" content="https://i.vimeocdn.com/video/452001751-1280x720.jpg">
+<meta property="og:video" content="http://player.vimeo.com/video/76979871">
+<meta property="og:video:secure_url" content="https://player.vimeo.com/video/76979871">
+<meta property="og:video:type" content="text/html">
+<meta property="og:video:width" content="1280">
+<meta property="og:video:height" content="720">
+<meta property="og:video" content="http://vimeo.com/moogaloop.swf?clip_id=76979871">
+<meta property="og:video:type" content="application/x-shockwave-flash">
+<meta property="og:video:width" content="1280">
+<meta property="og:video:height" content="720">
+</head></html>`,
+			want: []OpenGraphVideo{
+				{URL: "http://player.vimeo.com/video/76979871", SecureURL: "https://player.vimeo.com/video/76979871", Type: "text/html", Width: 1280, Height: 720},
+				{URL: "http://vimeo.com/moogaloop.swf?clip_id=76979871", Type: "application/x-shockwave-flash", Width: 1280, Height: 720},
+			},
+			preferred: []string{"https://player.vimeo.com/video/76979871", "http://vimeo.com/moogaloop.swf?clip_id=76979871"},
+		},
+	}
+	for _, tt := range tests {
+		og := ParseOpenGraph(parseString(t, tt.doc).Tags)
+		if og.Type != "video.other" {
+			t.Errorf("%s: Type = %q", tt.name, og.Type)
+		}
+		if !slices.Equal(og.Videos, tt.want) {
+			t.Errorf("%s: Videos = %+v, want %+v", tt.name, og.Videos, tt.want)
+			continue
+		}
+		for i, v := range og.Videos {
+			if got := v.PreferredURL(); got != tt.preferred[i] {
+				t.Errorf("%s: video %d PreferredURL() = %q, want %q", tt.name, i, got, tt.preferred[i])
+			}
+		}
+		if len(og.ImageDetails) != 1 || og.ImageDetails[0].Width != tt.imageWidth {
+			t.Errorf("%s: ImageDetails = %+v, want width %d", tt.name, og.ImageDetails, tt.imageWidth)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package htmlmetadata
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Option configures an Extractor.
+type Option func(*Extractor)
+
+// WithTransport sets the RoundTripper used for requests. A nil transport
+// selects http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(e *Extractor) {
+		e.transport = transport
+	}
+}
+
+// WithHTTPClient makes the Extractor send every request with client, as
+// configured, for applications that share one client. The client wins over
+// the options that would otherwise configure it, which are ignored, and each
+// one given adds a warning to the Result of every fetch:
+//
+//   - WithTransport, WithRoundTripWrapper and WithTimeout
+//   - WithFollowRedirects, WithMaxRedirects and WithSameHostRedirectsOnly
+//   - WithCookieJar and WithCookies
+//   - WithProxy, WithI2P and WithResolver
+//   - WithTLSConfig and WithInsecureSkipVerify
+//   - WithRateLimit and WithMaxConnsPerHost
+//
+// The client's own redirect policy applies, so WithRobotsPolicy does not
+// check redirect targets, and data: URLs are not decoded. Headers, retries
+// and everything after the response arrives work as usual.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Extractor) {
+		e.httpClient = client
+	}
+}
+
+// WithTimeout bounds the whole request, including reading the body. A zero
+// duration means no timeout. Requests that exceed it fail with an error
+// wrapping ErrTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(e *Extractor) {
+		e.timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request. An empty
+// string leaves the header to net/http's default.
+func WithUserAgent(ua string) Option {
+	return func(e *Extractor) {
+		e.userAgent = ua
+	}
+}
+
+// WithAccept sets the Accept header sent with page requests, by default
+// DefaultAccept, so that servers negotiating content return HTML rather than,
+// say, JSON that the content type check would reject. An empty string sends
+// no Accept header. robots.txt requests are sent without it, and an Accept
+// given to WithHeader takes precedence.
+func WithAccept(accept string) Option {
+	return func(e *Extractor) {
+		e.accept = accept
+	}
+}
+
+// WithReferer sets the Referer header sent with every request, typically the
+// page that links to the URL, for sites whose hotlink protection otherwise
+// serves placeholder content. It takes precedence over a Referer given to
+// WithHeader. The header follows redirects, except from an https URL to an
+// http one, where browsers drop it too.
+func WithReferer(referer string) Option {
+	return func(e *Extractor) {
+		e.referer = referer
+	}
+}
+
+// WithOrigin sets the Origin header sent with every request, such as
+// "https://example.com", for CDNs that check it. It takes precedence over an
+// Origin given to WithHeader. The header is dropped if a redirect leaves the
+// origin of the URL originally requested.
+func WithOrigin(origin string) Option {
+	return func(e *Extractor) {
+		e.origin = origin
+	}
+}
+
+// WithMaxBodySize caps how many bytes of a response body are read. Larger
+// bodies fail with ErrBodyTooLarge. A non-positive n removes the limit.
+func WithMaxBodySize(n int64) Option {
+	return func(e *Extractor) {
+		e.maxBodySize = n
+	}
+}
+
+// DefaultMaxRedirects is the redirect chain length followed unless
+// WithMaxRedirects overrides it.
+const DefaultMaxRedirects = 10
+
+// WithFollowRedirects controls whether redirects are followed. When disabled,
+// a 3xx response is returned as a Result with its Location set instead of an
+// error.
+func WithFollowRedirects(follow bool) Option {
+	return func(e *Extractor) {
+		e.noRedirects = !follow
+	}
+}
+
+// WithMaxRedirects sets how many redirects are followed before the request
+// fails with an error matching ErrTooManyRedirects. A value of zero or less
+// disables following, like WithFollowRedirects(false).
+func WithMaxRedirects(n int) Option {
+	return func(e *Extractor) {
+		e.maxRedirects = n
+	}
+}
+
+// WithSameHostRedirectsOnly, when enabled, follows only redirects that stay
+// on the host of the URL requested, such as from http to https or from one
+// path to another. A redirect to any other host, www. variants and
+// subdomains included, ends the chain without error: its 3xx response is
+// returned as a Result with Location set to the off-host target, as
+// WithFollowRedirects(false) does for every redirect. WithMaxRedirects still
+// bounds the redirects followed.
+func WithSameHostRedirectsOnly(enabled bool) Option {
+	return func(e *Extractor) {
+		e.sameHost = enabled
+	}
+}
+
+// WithAcceptStatus selects which HTTP status codes have their body parsed.
+// By default only 200 is accepted and any other status fails with a
+// StatusError. Accepting e.g. 404 lets soft-404 and branded error pages yield
+// their meta tags; Result.StatusCode still records the actual status.
+func WithAcceptStatus(accept func(code int) bool) Option {
+	return func(e *Extractor) {
+		e.acceptStatus = accept
+	}
+}
+
+// WithContentTypes replaces the media types whose bodies are parsed, by
+// default DefaultContentTypes. Responses of any other type fail with
+// ErrNotHTML before the body is read. A response without a Content-Type is
+// judged by the type http.DetectContentType sniffs from the start of its
+// body instead.
+func WithContentTypes(types ...string) Option {
+	return func(e *Extractor) {
+		e.contentTypes = types
+	}
+}
+
+// WithForceHTML parses every response body as HTML when force is true,
+// whatever its Content-Type, for servers that label real pages text/plain or
+// application/octet-stream. It overrides WithContentTypes and the sniffing
+// of bodies served without a Content-Type; a charset parameter in the header
+// is still honoured.
+func WithForceHTML(force bool) Option {
+	return func(e *Extractor) {
+		e.forceHTML = force
+	}
+}
+
+// WithDefaultCharset sets the encoding assumed for documents that declare none,
+// through a byte order mark, the Content-Type header or a meta tag, in place
+// of UTF-8. label is a WHATWG encoding label such as "windows-1251" or
+// "shift_jis"; an unknown label leaves the default unchanged.
+func WithDefaultCharset(label string) Option {
+	return func(e *Extractor) {
+		if enc, _ := charset.Lookup(label); enc != nil {
+			e.defaultCharset = enc
+		}
+	}
+}
+
+// WithAllowedSchemes replaces the URL schemes that may be fetched, by default
+// DefaultSchemes. data: URLs are decoded without a network request once
+// "data" is allowed. Other schemes, such as file, need a transport that
+// handles them; see WithTransport. URLs with any other scheme fail with
+// ErrInvalidScheme before a request is made.
+func WithAllowedSchemes(schemes ...string) Option {
+	return func(e *Extractor) {
+		e.schemes = schemes
+	}
+}
+
+// WithHeader adds a header sent with every request, such as Cookie,
+// Accept-Language or Authorization. It may be given several times, and
+// repeated keys accumulate values. A User-Agent set this way takes precedence
+// over WithUserAgent.
+func WithHeader(key, value string) Option {
+	return func(e *Extractor) {
+		if e.header == nil {
+			e.header = make(http.Header)
+		}
+		e.header.Add(key, value)
+	}
+}
+
+// WithHeaders adds every header in h to all requests, like repeated calls to
+// WithHeader.
+func WithHeaders(h http.Header) Option {
+	return func(e *Extractor) {
+		for key, values := range h {
+			for _, value := range values {
+				WithHeader(key, value)(e)
+			}
+		}
+	}
+}
+
+// WithNameFilter restricts Tags to meta tags whose name satisfies keep, such
+// as those with an "og:" or "twitter:" prefix; others are dropped as they are
+// parsed rather than afterwards. keep sees the name as MetaTag.Name would
+// hold it: the name attribute, else property, else http-equiv, with property
+// first under WithPreferProperty. Filtered tags still set Charset and
+// Refresh, and still appear in RawMetaTags.
+func WithNameFilter(keep func(name string) bool) Option {
+	return func(e *Extractor) {
+		e.nameFilter = keep
+	}
+}
+
+// WithNormalizeNames trims and lowercases the name, property and http-equiv
+// attributes of meta tags when normalize is true, so that name="Description"
+// and name="DESCRIPTION" both yield a MetaTag named "description" and AsMap
+// keys them together. HTML treats meta names and http-equiv values
+// case-insensitively, and the OpenGraph and Twitter vocabularies are
+// lowercase by definition, so nothing standard is lost; only custom names
+// that differ by case alone are merged. The name filter of WithNameFilter
+// sees the normalized name.
+func WithNormalizeNames(normalize bool) Option {
+	return func(e *Extractor) {
+		e.normalizeNames = normalize
+	}
+}
+
+// WithStrictCanonical makes Result.Canonical skip canonical links and og:url
+// values that point at a host other than the page's, treating them as
+// mistakes, such as a template copied from another site, or as attempts to
+// claim another site's content. A leading "www." is ignored in the
+// comparison.
+func WithStrictCanonical(strict bool) Option {
+	return func(e *Extractor) {
+		e.strictCanonical = strict
+	}
+}
+
+// WithPreferProperty names a meta tag that has both a name and a property
+// attribute, such as <meta name="twitter:title" property="og:title">, after
+// its property when prefer is true. By default the name attribute wins,
+// whichever comes first in the markup. Both values are kept either way, in
+// MetaTag.NameAttr and MetaTag.Property, and ParseOpenGraph and
+// ParseTwitterCard find such a tag under the attribute carrying their prefix;
+// the option matters to lookups by Name, such as Get and BestDescription.
+func WithPreferProperty(prefer bool) Option {
+	return func(e *Extractor) {
+		e.preferProperty = prefer
+	}
+}
+
+// WithItemPropTags includes meta tags that only carry an itemprop attribute,
+// such as <meta itemprop="datePublished" content="2024-05-01">, when include
+// is true. Their Name is the itemprop value and their Source is
+// SourceItemProp. They are skipped by default because their meaning depends
+// on the microdata item they belong to, which Result.Items records; an
+// itemprop="description" may describe a product or a review rather than the
+// page. Microdata names are case-sensitive and are left alone by
+// WithNormalizeNames.
+func WithItemPropTags(include bool) Option {
+	return func(e *Extractor) {
+		e.itemProps = include
+	}
+}
+
+// WithKeepEmptyContent keeps meta tags whose content attribute is empty or
+// missing, such as <meta name="robots">. By default such tags are skipped.
+func WithKeepEmptyContent(keep bool) Option {
+	return func(e *Extractor) {
+		e.keepEmptyContent = keep
+	}
+}
+
+// WithMaxTagCount caps how many meta tags are kept per document, in
+// Result.Tags and in RawMetaTags, so that a hostile page cannot make memory
+// grow with the number of tags. Tags past the cap are dropped with a warning.
+// Result.Warnings is capped at n entries too, plus one summarizing what was
+// dropped. A value of zero or less means no cap.
+func WithMaxTagCount(n int) Option {
+	return func(e *Extractor) {
+		e.maxTagCount = n
+	}
+}
+
+// WithMaxContentLength truncates meta content, and the attribute values in
+// RawMetaTags, to at most n bytes, recording a warning for each truncated
+// tag. A value of zero or less means no limit.
+func WithMaxContentLength(n int) Option {
+	return func(e *Extractor) {
+		e.maxContentLength = n
+	}
+}
+
+// WithPreflightHead sends a HEAD request before each GET. If its headers show
+// a non-HTML Content-Type or a Content-Length above the body size limit, the
+// extraction fails with ErrNotHTML or ErrBodyTooLarge without downloading the
+// body. Servers that reject HEAD, for example with 405, fall back to a plain
+// GET.
+func WithPreflightHead(enabled bool) Option {
+	return func(e *Extractor) {
+		e.preflightHead = enabled
+	}
+}
+
+// WithRetry makes each request up to maxAttempts times, retrying network
+// errors and the statuses in DefaultRetryStatus (see WithRetryStatus). Before
+// retry number n it waits backoff(n), or DefaultBackoff(n) if backoff is nil,
+// unless the response carries a Retry-After header, which is honored instead.
+// Waiting stops early if the request's context is canceled.
+func WithRetry(maxAttempts int, backoff func(attempt int) time.Duration) Option {
+	return func(e *Extractor) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		e.maxAttempts = maxAttempts
+		e.backoff = backoff
+	}
+}
+
+// WithRetryStatus replaces the status codes retried under WithRetry.
+func WithRetryStatus(codes ...int) Option {
+	return func(e *Extractor) {
+		e.retryStatus = codes
+	}
+}
+
+// WithCache consults cache before fetching and stores successful responses in
+// it. Entries carrying an ETag or Last-Modified validator are revalidated with
+// If-None-Match or If-Modified-Since, and a 304 answer is served from the
+// cache.
+func WithCache(cache Cache) Option {
+	return func(e *Extractor) {
+		e.cache = cache
+	}
+}
+
+// WithDedup drops meta tags that exactly repeat an earlier tag, as Dedup does.
+func WithDedup(enabled bool) Option {
+	return func(e *Extractor) {
+		e.dedup = enabled
+	}
+}
+
+// WithTrimContent strips leading and trailing whitespace from meta content.
+// It runs after entity decoding, so a decoded &nbsp; counts as whitespace.
+func WithTrimContent(enabled bool) Option {
+	return func(e *Extractor) {
+		e.trimContent = enabled
+	}
+}
+
+// WithCollapseWhitespace trims meta content and replaces each internal run of
+// whitespace, including newlines, tabs and decoded &nbsp;, with a single
+// space. It implies WithTrimContent.
+func WithCollapseWhitespace(enabled bool) Option {
+	return func(e *Extractor) {
+		e.collapseWhitespace = enabled
+	}
+}
+
+// WithRoundTripWrapper wraps the transport, after all other transport
+// options have been applied, for instrumentation such as
+// otelhttp.NewTransport. Wrappers are applied in the order given, so the last
+// one is outermost.
+//
+// Requests are built from the caller's context, so a net/http/httptrace
+// ClientTrace or a tracing span carried by the context passed to
+// ExtractContext is visible to the wrapper and the transport.
+func WithRoundTripWrapper(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(e *Extractor) {
+		e.wrappers = append(e.wrappers, wrap)
+	}
+}
+
+// WithHooks installs callbacks that observe fetches and parse warnings.
+func WithHooks(hooks Hooks) Option {
+	return func(e *Extractor) {
+		e.hooks = hooks
+	}
+}
+
+// WithVisitor registers v to receive every element during parsing, after the
+// built-in extraction. It may be given several times; visitors run in the
+// order registered. A visitor shared between concurrent extractions must
+// synchronize its own state.
+func WithVisitor(v TagVisitor) Option {
+	return func(e *Extractor) {
+		e.visitors = append(e.visitors, v)
+	}
+}
+
+// WithCanonicalizeURLs normalizes URL-valued meta tags, such as og:url, and
+// link hrefs after they are resolved: the scheme and host are lowercased, a
+// default port is dropped and the query parameters named by stripParams are
+// removed. A name ending in "*" matches by prefix, so "utm_*" removes
+// utm_source and friends. With no names, DefaultTrackingParams is used.
+func WithCanonicalizeURLs(stripParams ...string) Option {
+	return func(e *Extractor) {
+		e.canonicalize = true
+		e.stripParams = stripParams
+		if len(stripParams) == 0 {
+			e.stripParams = DefaultTrackingParams
+		}
+	}
+}
+
+// WithReadingMetrics makes full-document parsing count the words of the page
+// text and estimate its reading time at wordsPerMinute, filling
+// Result.WordCount and Result.ReadingTime. A value of zero or less uses
+// DefaultWordsPerMinute.
+func WithReadingMetrics(wordsPerMinute int) Option {
+	return func(e *Extractor) {
+		if wordsPerMinute <= 0 {
+			wordsPerMinute = DefaultWordsPerMinute
+		}
+		e.wordsPerMinute = wordsPerMinute
+	}
+}
+
+// WithCookieJar makes the client store cookies from responses in jar and send
+// them with later requests, including the requests of a redirect chain. This
+// lets pages that set a consent cookie and then redirect to themselves serve
+// their real content. Without a jar, cookies are ignored.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(e *Extractor) {
+		e.jar = jar
+	}
+}
+
+// WithCookies seeds the cookie jar with cookies to send to u, such as a
+// session or consent cookie obtained elsewhere. If no jar is set with
+// WithCookieJar, an in-memory one from net/http/cookiejar is created.
+func WithCookies(u *url.URL, cookies ...*http.Cookie) Option {
+	return func(e *Extractor) {
+		e.initialCookies = append(e.initialCookies, initialCookies{u, cookies})
+	}
+}
+
+// initialCookies holds cookies given to WithCookies until New installs them.
+type initialCookies struct {
+	url     *url.URL
+	cookies []*http.Cookie
+}
+
+// WithScanFullDocument controls whether Extract and the other convenience
+// methods parse the whole document, the default, or stop at the end of
+// <head> as ParseHead does. Stopping early avoids building a DOM and, for
+// fetched pages, downloading the rest of the body, which on large pages is
+// most of the cost. It misses meta tags that pages misplace in <body>, as
+// well as microdata, RDFa and reading metrics, which need the body.
+func WithScanFullDocument(full bool) Option {
+	return func(e *Extractor) {
+		e.headOnly = !full
+	}
+}
+
+// WithRetainBody controls whether the body of fetched pages is kept in
+// Result.Body, so they can be archived without a second request. It is off
+// by default. The body is buffered in full before parsing, which costs
+// memory up to the maximum body size per extraction.
+func WithRetainBody(enabled bool) Option {
+	return func(e *Extractor) {
+		e.retainBody = enabled
+	}
+}
@@ -0,0 +1,61 @@
+package htmlmetadata
+
+import "context"
+
+// PageMetadata gathers the metadata most consumers want from a page, each
+// part derived from a Result by its own helper.
+type PageMetadata struct {
+	// URL is the address the page was fetched from, after redirects.
+	URL string `json:"url,omitempty"`
+	// Title, Description and Image are chosen by BestTitle, BestDescription
+	// and BestImage with their default orders.
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	// Canonical is the preferred URL of the page, as returned by
+	// Result.Canonical.
+	Canonical string `json:"canonical,omitempty"`
+	Lang      string `json:"lang,omitempty"`
+
+	OpenGraph OpenGraph                `json:"opengraph"`
+	Twitter   TwitterCard              `json:"twitter"`
+	Icons     []Icon                   `json:"icons,omitempty"`
+	Feeds     []Feed                   `json:"feeds,omitempty"`
+	JSONLD    []map[string]interface{} `json:"jsonld,omitempty"`
+	// Tags holds every meta tag, in document order.
+	Tags []MetaTag `json:"tags,omitempty"`
+
+	// Result is the extraction the fields were derived from, for everything
+	// else: links, microdata, warnings and response details.
+	Result *Result `json:"-"`
+}
+
+// Page derives a PageMetadata from r.
+func (r *Result) Page() *PageMetadata {
+	return &PageMetadata{
+		URL:         r.URL,
+		Title:       r.BestTitle(),
+		Description: r.BestDescription(),
+		Image:       r.BestImage(),
+		Canonical:   r.Canonical(),
+		Lang:        r.Lang,
+		OpenGraph:   ParseOpenGraph(r.Tags),
+		Twitter:     ParseTwitterCard(r.Tags),
+		Icons:       r.Icons(),
+		Feeds:       r.Feeds(),
+		JSONLD:      r.JSONLD,
+		Tags:        r.Tags,
+		Result:      r,
+	}
+}
+
+// ExtractPage fetches the page at rawURL, as ExtractResponseContext does,
+// and returns its metadata in one value. For documents already at hand,
+// call Page on the Result of Parse.
+func (e *Extractor) ExtractPage(ctx context.Context, rawURL string) (*PageMetadata, error) {
+	res, err := e.ExtractResponseContext(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return res.Page(), nil
+}
@@ -0,0 +1,41 @@
+package htmlmetadata
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPageJSONKeys(t *testing.T) {
+	const doc = `<html><head>
+<link rel="icon" href="https://example.com/icon.png" sizes="32x32">
+<link rel="alternate" type="application/rss+xml" title="News" href="https://example.com/feed.xml">
+</head></html>`
+	data, err := json.Marshal(parseString(t, doc).Page())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var page struct {
+		Icons []map[string]json.RawMessage `json:"icons"`
+		Feeds []map[string]json.RawMessage `json:"feeds"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(page.Icons) != 1 || len(page.Feeds) != 1 {
+		t.Fatalf("got %s", data)
+	}
+	for _, obj := range []map[string]json.RawMessage{page.Icons[0], page.Feeds[0]} {
+		for key := range obj {
+			if key != strings.ToLower(key) {
+				t.Errorf("key %q is not snake_case in %s", key, data)
+			}
+		}
+		if _, ok := obj["url"]; !ok {
+			t.Errorf("no url key in %s", data)
+		}
+	}
+	if !strings.Contains(string(data), `"sizes":[{"width":32,"height":32}]`) {
+		t.Errorf("icon sizes not snake_case in %s", data)
+	}
+}
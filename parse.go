@@ -0,0 +1,762 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// parse parses HTML content and collects the title and meta tags. contentType
+// is the Content-Type header the body was served with, if any, and is used to
+// pick the character encoding. pageURL is the address the document was
+// fetched from, if known, and is used to resolve relative URLs.
+func (e *Extractor) parse(r io.Reader, contentType, pageURL string, header http.Header) (res *Result, err error) {
+	start := time.Now()
+	c := e.newCollector(pageURL, header)
+	defer c.recoverPanic(&res, &err)
+	r, err = newUTF8Reader(r, contentType, e.defaultCharset)
+	if err != nil {
+		return nil, err
+	}
+
+	r, xhtml := xhtmlReader(r, contentType)
+	var doc *html.Node
+	if xhtml {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+		if doc, err = parseXHTML(data, false); err != nil {
+			c.res.warnf("malformed XHTML parsed as HTML: %v", err)
+			r = bytes.NewReader(data)
+		}
+	}
+	if doc == nil {
+		if doc, err = html.Parse(r); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrParse, err)
+		}
+	}
+
+	return e.extract(c, doc, pageURL, start), nil
+}
+
+// parseFragment parses r as a fragment of the <body> of a document, without
+// the implied <html> and <head> elements of a full parse.
+func (e *Extractor) parseFragment(r io.Reader) (res *Result, err error) {
+	start := time.Now()
+	c := e.newCollector("", nil)
+	defer c.recoverPanic(&res, &err)
+	r, err = newUTF8Reader(r, "", e.defaultCharset)
+	if err != nil {
+		return nil, err
+	}
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	nodes, err := html.ParseFragment(r, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	doc := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		doc.AppendChild(n)
+	}
+	return e.extract(c, doc, "", start), nil
+}
+
+// extract walks a parsed document with c and the registered visitors and
+// completes the Result. start is when parsing began.
+func (e *Extractor) extract(c *collector, doc *html.Node, pageURL string, start time.Time) *Result {
+	visitors := append([]TagVisitor{c}, e.visitors...)
+	visit(doc, visitors)
+
+	res := c.finish(pageURL)
+	res.Items = extractMicrodata(doc, res.base)
+	res.RDFa = extractRDFa(doc, res.base)
+	if e.wordsPerMinute > 0 {
+		res.WordCount = countWords(doc)
+		res.ReadingTime = time.Duration(res.WordCount) * time.Minute / time.Duration(e.wordsPerMinute)
+	}
+	res.ParseDuration = time.Since(start)
+	return res
+}
+
+// scan parses r with the whole-document parser, or only its head if
+// WithScanFullDocument(false) is set.
+func (e *Extractor) scan(r io.Reader, contentType, pageURL string, header http.Header) (*Result, error) {
+	if e.headOnly {
+		return e.parseHead(r, contentType, pageURL, header)
+	}
+	return e.parse(r, contentType, pageURL, header)
+}
+
+// parseContext is scan bounded by ctx. If ctx is done before parsing
+// finishes, it returns ctx.Err() straight away. The parse itself cannot be
+// interrupted and runs to completion in the background, but its result is
+// discarded.
+func (e *Extractor) parseContext(ctx context.Context, r io.Reader, contentType, pageURL string, header http.Header) (*Result, error) {
+	if ctx.Done() == nil {
+		return e.scan(r, contentType, pageURL, header)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type outcome struct {
+		res *Result
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := e.scan(r, contentType, pageURL, header)
+		done <- outcome{res, err}
+	}()
+	select {
+	case o := <-done:
+		return o.res, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// parseHead scans r with a tokenizer instead of building a DOM, stopping at
+// the end of <head>. It collects the same data as parse for elements that
+// appear before that point.
+func (e *Extractor) parseHead(r io.Reader, contentType, pageURL string, header http.Header) (res *Result, err error) {
+	start := time.Now()
+	c := e.newCollector(pageURL, header)
+	defer c.recoverPanic(&res, &err)
+	r, err = newUTF8Reader(r, contentType, e.defaultCharset)
+	if err != nil {
+		return nil, err
+	}
+
+	visitors := append([]TagVisitor{c}, e.visitors...)
+	done := func() (*Result, error) {
+		res := c.finish(pageURL)
+		res.ParseDuration = time.Since(start)
+		return res, nil
+	}
+	// The tokenizer would read past a self-closing <script/> or <title/>
+	r, xhtml := xhtmlReader(r, contentType)
+	if xhtml {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+		doc, err := parseXHTML(data, true)
+		if err == nil {
+			visit(doc, visitors)
+			return done()
+		}
+		c.res.warnf("malformed XHTML parsed as HTML: %v", err)
+		r = bytes.NewReader(data)
+	}
+	if err := tokenize(html.NewTokenizer(r), visitors, true, nil); err != nil {
+		return nil, err
+	}
+	return done()
+}
+
+// parseEach scans the whole of r with the tokenizer, as parseHead does the
+// head, and passes each meta tag to fn as soon as it is read. It returns
+// without reading further once fn returns false.
+func (e *Extractor) parseEach(r io.Reader, fn func(MetaTag) bool) (err error) {
+	c := e.newCollector("", nil)
+	c.onTag = fn
+	defer func() { e.hooks.parseWarnings(c.res.Warnings) }()
+	defer c.recoverPanic(nil, &err)
+	r, err = newUTF8Reader(r, "", e.defaultCharset)
+	if err != nil {
+		return err
+	}
+
+	visitors := []TagVisitor{c}
+	r, xhtml := xhtmlReader(r, "")
+	if xhtml {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read body: %w", err)
+		}
+		doc, err := parseXHTML(data, false)
+		if err == nil {
+			visit(doc, visitors)
+			return nil
+		}
+		c.res.warnf("malformed XHTML parsed as HTML: %v", err)
+		r = bytes.NewReader(data)
+	}
+	return tokenize(html.NewTokenizer(r), visitors, false, func() bool { return c.stopped })
+}
+
+// tokenize feeds the elements read by z to visitors without building a DOM.
+// Only <title> and <script> get their text as a child, and the content of
+// <noscript> is parsed and visited as well. With headOnly it returns at the
+// end of <head> or at the first element that belongs in the body; otherwise
+// it reads to the end of the document. stop, if not nil, is checked after
+// every element and ends the scan when it reports true.
+func tokenize(z *html.Tokenizer, visitors []TagVisitor, headOnly bool, stop func() bool) error {
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return fmt.Errorf("%w: %w", ErrParse, err)
+			}
+			return nil
+		case html.EndTagToken:
+			if name, _ := z.TagName(); headOnly && atom.Lookup(name) == atom.Head {
+				return nil
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			n := &html.Node{Type: html.ElementNode, DataAtom: tok.DataAtom, Data: tok.Data, Attr: tok.Attr}
+			var noscript []*html.Node
+			switch tok.DataAtom {
+			case atom.Html, atom.Head, atom.Meta, atom.Link, atom.Base, atom.Style, atom.Template:
+			case atom.Title, atom.Script:
+				n.AppendChild(&html.Node{Type: html.TextNode, Data: rawText(z, tok.DataAtom)})
+			case atom.Noscript:
+				noscript = noscriptContent(rawText(z, atom.Noscript))
+			default:
+				// Anything else, <body> included, starts the body
+				if headOnly {
+					return nil
+				}
+			}
+			for _, v := range visitors {
+				v.Visit(n)
+			}
+			for _, child := range noscript {
+				visit(child, visitors)
+			}
+			if stop != nil && stop() {
+				return nil
+			}
+		}
+	}
+}
+
+// visit passes n and every element beneath it to visitors in document order.
+// The content of <noscript> elements is parsed and visited as well. The walk
+// keeps its own stack, so deeply nested documents cannot exhaust the
+// goroutine's.
+func visit(n *html.Node, visitors []TagVisitor) {
+	sp := stackPool.Get().(*[]*html.Node)
+	*sp = walk(n, visitors, *sp)
+	putStack(sp)
+}
+
+// walk does the work of visit, growing stack as needed, and returns it.
+func walk(n *html.Node, visitors []TagVisitor, stack []*html.Node) []*html.Node {
+	stack = append(stack, n)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		stack = pushChildren(stack, n)
+		if n.Type != html.ElementNode {
+			continue
+		}
+		for _, v := range visitors {
+			v.Visit(n)
+		}
+		if n.DataAtom == atom.Noscript && n.Namespace == "" {
+			// Visited before the element's own children, its raw text
+			nodes := noscriptContent(textContent(n))
+			for i := len(nodes) - 1; i >= 0; i-- {
+				stack = append(stack, nodes[i])
+			}
+		}
+	}
+	return stack
+}
+
+// stackPool holds the node stacks of finished walks for reuse, sparing high
+// volume callers the allocations of growing a fresh one per document.
+var stackPool = sync.Pool{
+	New: func() any {
+		stack := make([]*html.Node, 0, 64)
+		return &stack
+	},
+}
+
+// maxPooledStack is the largest stack capacity kept in stackPool, so one
+// pathological document does not pin a huge slice.
+const maxPooledStack = 4096
+
+// putStack empties the stack in sp, dropping its references to nodes, and
+// returns it to stackPool.
+func putStack(sp *[]*html.Node) {
+	if cap(*sp) > maxPooledStack {
+		return
+	}
+	stack := (*sp)[:cap(*sp)]
+	clear(stack)
+	*sp = stack[:0]
+	stackPool.Put(sp)
+}
+
+// pushChildren pushes the children of n onto stack so that they pop in
+// document order.
+func pushChildren(stack []*html.Node, n *html.Node) []*html.Node {
+	for child := n.LastChild; child != nil; child = child.PrevSibling {
+		stack = append(stack, child)
+	}
+	return stack
+}
+
+// noscriptContent parses the text of a <noscript> element as markup. The
+// parser assumes scripting is enabled, so it keeps the content of <noscript>
+// as raw text, which would hide the meta tags pages put there for visitors
+// without JavaScript, such as tracking pixels and site verifications.
+func noscriptContent(text string) []*html.Node {
+	if text == "" {
+		return nil
+	}
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	nodes, err := html.ParseFragment(strings.NewReader(text), body)
+	if err != nil {
+		return nil
+	}
+	return nodes
+}
+
+// rawText collects the text of the element the tokenizer has just entered, up
+// to its end tag.
+func rawText(z *html.Tokenizer, a atom.Atom) string {
+	var sb strings.Builder
+	for {
+		switch z.Next() {
+		case html.TextToken:
+			sb.Write(z.Text())
+		case html.EndTagToken:
+			if name, _ := z.TagName(); atom.Lookup(name) == a {
+				return strings.TrimSpace(sb.String())
+			}
+		case html.ErrorToken:
+			return strings.TrimSpace(sb.String())
+		}
+	}
+}
+
+// collector accumulates a Result from elements in document order. It is the
+// built-in TagVisitor; both the DOM walk and the tokenizer feed it, so they
+// extract identical data.
+type collector struct {
+	e         *Extractor
+	res       *Result
+	titleSeen bool
+	baseHref  string
+	// droppedTags counts the meta tags beyond the maximum tag count.
+	droppedTags int
+	// onTag, if set, receives each meta tag in place of Result.Tags, and
+	// stopped records that it asked for no more.
+	onTag   func(MetaTag) bool
+	stopped bool
+}
+
+// newCollector returns a collector for a document fetched from pageURL with
+// the response header, both unknown for documents that were not fetched.
+func (e *Extractor) newCollector(pageURL string, header http.Header) *collector {
+	res := &Result{rawLimit: e.maxContentLength, strictCanonical: e.strictCanonical, maxWarnings: e.maxTagCount}
+	res.Links = headerLinks(header, pageURL)
+	res.XRobotsTag = slices.Clone(header.Values("X-Robots-Tag"))
+	return &collector{e: e, res: res}
+}
+
+// recoverPanic, deferred by the parse functions, turns a panic while parsing,
+// in this package or in a TagVisitor, into a *PanicError stored in *err. If
+// res is not nil, *res is set to what c has collected so far.
+func (c *collector) recoverPanic(res **Result, err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	*err = &PanicError{Value: v, Stack: debug.Stack()}
+	if res != nil {
+		*res = c.res
+	}
+}
+
+// Visit dispatches an element to its handler.
+func (c *collector) Visit(n *html.Node) {
+	// Elements from foreign content such as SVG share names like <title>
+	if n.Namespace != "" {
+		return
+	}
+	switch n.DataAtom {
+	case atom.Html:
+		c.html(n.Attr)
+	case atom.Meta:
+		c.meta(n.Attr)
+	case atom.Title:
+		c.title(textContent(n))
+	case atom.Link:
+		c.link(n.Attr)
+	case atom.Script:
+		c.script(n.Attr, textContent(n))
+	case atom.Base:
+		c.base(n.Attr)
+	case atom.A:
+		if c.e.anchors {
+			c.anchor(n)
+		}
+	}
+}
+
+// html handles the root <html> element.
+func (c *collector) html(attrs []html.Attribute) {
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "amp", "⚡":
+			c.res.IsAMP = true
+		case "lang":
+			c.res.Lang = strings.TrimSpace(attr.Val)
+		}
+	}
+}
+
+// meta handles a <meta> element.
+func (c *collector) meta(attrs []html.Attribute) {
+	if c.stopped {
+		return
+	}
+	if c.e.maxTagCount <= 0 || len(c.res.rawMeta) < c.e.maxTagCount {
+		// Converted to maps only if RawMetaTags is called
+		c.res.rawMeta = append(c.res.rawMeta, attrs)
+	}
+
+	var name, property, httpEquiv, itemProp, content, charset, media string
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "charset":
+			charset = attr.Val
+		case "media":
+			media = attr.Val
+		case "name":
+			name = attr.Val
+		case "property":
+			property = attr.Val
+		case "http-equiv":
+			httpEquiv = attr.Val
+		case "itemprop":
+			itemProp = attr.Val
+		case "content":
+			content = attr.Val
+		}
+	}
+	if c.e.normalizeNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		property = strings.ToLower(strings.TrimSpace(property))
+		httpEquiv = strings.ToLower(strings.TrimSpace(httpEquiv))
+	}
+	// itemProp names the tag only if WithItemPropTags is set
+	itemName := ""
+	if c.e.itemProps {
+		itemName = itemProp
+	}
+	// The filter and warnings see the name the tag is stored under
+	tagName, source := name, SourceName
+	switch {
+	case name != "" && (property == "" || !c.e.preferProperty):
+	case property != "":
+		tagName, source = property, SourceProperty
+	case httpEquiv == "" && itemName != "":
+		tagName, source = itemName, SourceItemProp
+	default:
+		tagName, source = httpEquiv, SourceHTTPEquiv
+	}
+	// A filtered-out tag still matters if it declares a charset or refresh
+	skipped := c.e.nameFilter != nil && !c.e.nameFilter(tagName)
+	if skipped && httpEquiv == "" && charset == "" {
+		return
+	}
+	// A URL's query may spell out what look like entities
+	if !isURLValued(tagName) {
+		content = decodeEntities(content)
+	}
+	if n := c.e.maxContentLength; n > 0 && len(content) > n {
+		if !skipped {
+			c.res.warnf("content of %s truncated from %d bytes", tagName, len(content))
+		}
+		content = truncateUTF8(content, n)
+	}
+	switch {
+	case c.e.collapseWhitespace:
+		content = strings.Join(strings.Fields(content), " ")
+	case c.e.trimContent:
+		content = strings.TrimSpace(content)
+	}
+	if strings.EqualFold(httpEquiv, "refresh") && c.res.Refresh == nil {
+		c.res.Refresh = parseRefresh(content)
+		if c.res.Refresh == nil {
+			c.res.warnf("malformed refresh directive %q ignored", content)
+		}
+	}
+	if strings.EqualFold(httpEquiv, "content-type") {
+		if _, params, err := mime.ParseMediaType(content); err == nil {
+			charset = params["charset"]
+		}
+	}
+	charset = strings.TrimSpace(charset)
+	switch {
+	case c.res.Charset == "":
+		c.res.Charset = charset
+	case charset != "" && !strings.EqualFold(charset, c.res.Charset):
+		c.res.warnf("conflicting charset declarations %q and %q, using the first", c.res.Charset, charset)
+	}
+	tag := MetaTag{Name: tagName, Content: content, Property: property, NameAttr: name, ItemProp: itemProp, Source: source, Media: media}
+	if skipped {
+		return
+	}
+	// Whitespace-only content is as empty as a missing attribute
+	if strings.TrimSpace(content) == "" {
+		content, tag.Content = "", ""
+		if tag.Name != "" {
+			c.res.warnf("%s has no content", tag.Name)
+		}
+	}
+	if tag.Name == "" || (content == "" && !c.e.keepEmptyContent) {
+		return
+	}
+	if c.onTag != nil {
+		// Only a <base href> seen so far can resolve relative URLs
+		if isURLValued(tag.Name) {
+			tag.Content = c.resolveContent(documentBase("", c.baseHref), tag.Content)
+		}
+		c.stopped = !c.onTag(tag)
+		return
+	}
+	if c.e.maxTagCount > 0 && len(c.res.Tags) >= c.e.maxTagCount {
+		c.droppedTags++
+		return
+	}
+	c.res.Tags = append(c.res.Tags, tag)
+}
+
+// truncateUTF8 shortens s to at most n bytes, if n is positive, without
+// splitting a UTF-8 sequence.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// title handles a <title> element. Only the first one counts.
+func (c *collector) title(text string) {
+	if c.titleSeen {
+		c.res.warnf("duplicate <title> ignored")
+		return
+	}
+	c.titleSeen = true
+	c.res.Title = text
+}
+
+// link handles a <link> element.
+func (c *collector) link(attrs []html.Attribute) {
+	if link, ok := newLinkTag(attrs); ok {
+		c.res.Links = append(c.res.Links, link)
+	}
+}
+
+// script handles a <script> element.
+func (c *collector) script(attrs []html.Attribute, text string) {
+	if isJSONLDScript(attrs) {
+		c.res.addJSONLD(text)
+	}
+}
+
+// base handles a <base> element. Only the first one counts.
+func (c *collector) base(attrs []html.Attribute) {
+	href := attrValue(attrs, "href")
+	switch {
+	case c.baseHref == "":
+		c.baseHref = href
+	case href != "":
+		c.res.warnf("duplicate <base href> ignored")
+	}
+}
+
+// finish resolves relative URLs now that the document base is known and
+// returns the Result.
+func (c *collector) finish(pageURL string) *Result {
+	res := c.res
+	base := documentBase(pageURL, c.baseHref)
+	res.base = base
+	for i := range res.Tags {
+		if isURLValued(res.Tags[i].Name) {
+			res.Tags[i].Content = c.resolveContent(base, res.Tags[i].Content)
+		}
+	}
+	if res.Refresh != nil {
+		res.Refresh.URL = resolveURL(base, res.Refresh.URL)
+	}
+	if len(res.Anchors) > 0 {
+		c.finishAnchors(base)
+	}
+	if c.e.dedup {
+		res.Tags = Dedup(res.Tags)
+	}
+	// The summary is not itself subject to the cap
+	switch {
+	case res.droppedWarnings > 0:
+		res.Warnings = append(res.Warnings, fmt.Sprintf("tag limit of %d reached, %d more tags and %d more warnings dropped", c.e.maxTagCount, c.droppedTags, res.droppedWarnings))
+	case c.droppedTags > 0:
+		res.Warnings = append(res.Warnings, fmt.Sprintf("tag limit of %d reached, %d more tags dropped", c.e.maxTagCount, c.droppedTags))
+	}
+	for i := range res.Links {
+		res.Links[i].Href = resolveURL(base, res.Links[i].Href)
+		if c.e.canonicalize {
+			res.Links[i].Href = canonicalizeURL(res.Links[i].Href, c.e.stripParams)
+		}
+		if res.AMPURL == "" && res.Links[i].HasRel("amphtml") {
+			res.AMPURL = res.Links[i].Href
+		}
+	}
+	if n := countCanonicals(res.Links); n > 1 {
+		res.warnf("duplicate canonical link: %d found", n)
+	}
+	c.e.hooks.parseWarnings(res.Warnings)
+	return res
+}
+
+// resolveContent resolves the URL in the content of a URL-valued tag against
+// base, canonicalizing it if the Extractor is configured to.
+func (c *collector) resolveContent(base *url.URL, content string) string {
+	content = resolveURL(base, strings.TrimSpace(content))
+	if c.e.canonicalize {
+		content = canonicalizeURL(content, c.e.stripParams)
+	}
+	return content
+}
+
+// countCanonicals counts the canonical links, leaving out those from a Link
+// header that repeat the target of a <link> element.
+func countCanonicals(links []LinkTag) int {
+	var inDocument []string
+	for _, link := range links {
+		if link.HasRel("canonical") && !link.FromHeader {
+			inDocument = append(inDocument, link.Href)
+		}
+	}
+	n := len(inDocument)
+	for _, link := range links {
+		if link.HasRel("canonical") && link.FromHeader && !slices.Contains(inDocument, link.Href) {
+			n++
+		}
+	}
+	return n
+}
+
+// warnf records a non-fatal problem with the document, or counts it once
+// maxWarnings have been recorded.
+func (r *Result) warnf(format string, args ...interface{}) {
+	if r.maxWarnings > 0 && len(r.Warnings) >= r.maxWarnings {
+		r.droppedWarnings++
+		return
+	}
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// decodeEntities unescapes character references left in an attribute value.
+// The parser has already decoded one level, so this only affects content that
+// was escaped twice, such as "Tom &amp;amp; Jerry". Only complete references
+// ending in a semicolon, such as &amp; and &#39;, are decoded: legacy ones
+// without it, such as the &not of "?a=1&notify=1", are left alone, since
+// what the parser left behind is far more likely to be text.
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '&')
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		n := entityLen(s)
+		if n == 0 {
+			b.WriteByte('&')
+			s = s[1:]
+			continue
+		}
+		b.WriteString(html.UnescapeString(s[:n]))
+		s = s[n:]
+	}
+}
+
+// entityLen returns the length of the character reference s starts with:
+// "&name;", "&#digits;" or "&#xhex;". It returns 0 if s does not start with
+// a complete one.
+func entityLen(s string) int {
+	i, valid := 1, func(c byte) bool { return c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'z' }
+	switch {
+	case strings.HasPrefix(s, "&#x"), strings.HasPrefix(s, "&#X"):
+		i, valid = 3, func(c byte) bool { return c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'f' }
+	case strings.HasPrefix(s, "&#"):
+		i, valid = 2, func(c byte) bool { return c >= '0' && c <= '9' }
+	}
+	start := i
+	for i < len(s) && valid(s[i]) {
+		i++
+	}
+	if i == start || i == len(s) || s[i] != ';' {
+		return 0
+	}
+	return i + 1
+}
+
+// attrValue returns the value of attribute key, or "" if it is absent. key
+// must be lowercase: the parser and tokenizer lowercase attribute names, so
+// <META NAME="x"> matches "name".
+func attrValue(attrs []html.Attribute, key string) string {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// textContent concatenates the text nodes beneath n. Entities are already
+// unescaped by the parser.
+func textContent(n *html.Node) string {
+	// A lone text child, as in most titles and scripts, needs no copy
+	if c := n.FirstChild; c != nil && c == n.LastChild && c.Type == html.TextNode {
+		return strings.TrimSpace(c.Data)
+	}
+	var sb strings.Builder
+	sp := stackPool.Get().(*[]*html.Node)
+	stack := pushChildren(*sp, n)
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch c.Type {
+		case html.TextNode:
+			sb.WriteString(c.Data)
+		case html.ElementNode:
+			stack = pushChildren(stack, c)
+		}
+	}
+	*sp = stack
+	putStack(sp)
+	return strings.TrimSpace(sb.String())
+}
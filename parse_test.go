@@ -0,0 +1,257 @@
+package htmlmetadata
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// parseString parses doc with an Extractor built from opts, failing the test
+// on error.
+func parseString(t *testing.T, doc string, opts ...Option) *Result {
+	t.Helper()
+	res, err := New(opts...).Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return res
+}
+
+func TestNamePropertyPrecedence(t *testing.T) {
+	const doc = `<html><head><meta property="og:title" name="twitter:title" content="Both"></head></html>`
+	tests := []struct {
+		prefer bool
+		name   string
+		source AttrSource
+	}{
+		{false, "twitter:title", SourceName},
+		{true, "og:title", SourceProperty},
+	}
+	for _, tt := range tests {
+		res := parseString(t, doc, WithPreferProperty(tt.prefer))
+		if len(res.Tags) != 1 {
+			t.Fatalf("prefer=%v: got %d tags, want 1", tt.prefer, len(res.Tags))
+		}
+		tag := res.Tags[0]
+		if tag.Name != tt.name || tag.Source != tt.source {
+			t.Errorf("prefer=%v: got %s from %s, want %s from %s", tt.prefer, tag.Name, tag.Source, tt.name, tt.source)
+		}
+		if tag.NameAttr != "twitter:title" || tag.Property != "og:title" {
+			t.Errorf("prefer=%v: raw attributes %q, %q not kept", tt.prefer, tag.NameAttr, tag.Property)
+		}
+		if got := ParseOpenGraph(res.Tags).Title; got != "Both" {
+			t.Errorf("prefer=%v: og:title = %q", tt.prefer, got)
+		}
+		if got := ParseTwitterCard(res.Tags).Title; got != "Both" {
+			t.Errorf("prefer=%v: twitter:title = %q", tt.prefer, got)
+		}
+
+		// The filter sees the name the tag is stored under
+		filtered := parseString(t, doc, WithPreferProperty(tt.prefer), WithNameFilter(func(name string) bool {
+			return name == tt.name
+		}))
+		if len(filtered.Tags) != 1 {
+			t.Errorf("prefer=%v: filter on %q kept %d tags, want 1", tt.prefer, tt.name, len(filtered.Tags))
+		}
+	}
+}
+
+func TestContentEntities(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"description", "Tom &amp; Jerry", "Tom & Jerry"},
+		{"description", "Tom &amp;amp; Jerry", "Tom & Jerry"},
+		{"description", "&quot;Quoted&quot; &lt;b&gt;", `"Quoted" <b>`},
+		{"description", "It&#39;s", "It's"},
+		{"description", "It&amp;#39;s", "It's"},
+		{"description", "It&amp;#x27;s &amp;#X27;", "It's '"},
+		{"description", "caf&#233; &amp;eacute;", "café é"},
+		// Legacy entities without a semicolon are text after the first decode
+		{"description", "a=1&amp;notify=1&amp;copy=2", "a=1&notify=1&copy=2"},
+		{"description", "fish &amp; chips &amp;", "fish & chips &"},
+		{"description", "&amp;#;&amp;#x;&amp;;", "&#;&#x;&;"},
+		// URL-valued content is decoded once, by the parser
+		{"og:image", "https://example.com/i.png?a=1&amp;notify=1&amp;copy=2&amp;times=3", "https://example.com/i.png?a=1&notify=1&copy=2&times=3"},
+		{"og:url", "https://example.com/?q=a&amp;amp;b", "https://example.com/?q=a&amp;b"},
+	}
+	for _, tt := range tests {
+		tags, err := New().ExtractFromString(`<meta property="` + tt.name + `" content="` + tt.content + `">`)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.content, err)
+		}
+		if len(tags) != 1 || tags[0].Content != tt.want {
+			t.Errorf("%s %q: got %v, want %q", tt.name, tt.content, tags, tt.want)
+		}
+	}
+}
+
+func TestMaxTagCountCapsWarnings(t *testing.T) {
+	doc := strings.Repeat(`<meta name="a">`, 1000) + strings.Repeat(`<meta name="b" content="x">`, 20)
+	res := parseString(t, doc, WithMaxTagCount(10))
+	if len(res.Tags) != 10 {
+		t.Errorf("got %d tags, want 10", len(res.Tags))
+	}
+	if len(res.Warnings) != 11 {
+		t.Fatalf("got %d warnings, want 10 and a summary", len(res.Warnings))
+	}
+	want := "tag limit of 10 reached, 10 more tags and 990 more warnings dropped"
+	if got := res.Warnings[10]; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+// BenchmarkVisit compares walks reusing pooled stacks with walks growing a
+// fresh stack each time, as they did before stackPool.
+func BenchmarkVisit(b *testing.B) {
+	doc, err := html.Parse(bytes.NewReader(benchmarkDocument()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			visit(doc, nil)
+		}
+	})
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			walk(doc, nil, nil)
+		}
+	})
+}
+
+func TestDeeplyNestedDocument(t *testing.T) {
+	// The parser itself slows quadratically with depth, so this fixture stays
+	// modest; TestVisitDeepTree covers the walk at a depth that matters
+	const depth = 5000
+	doc := `<html><head><title>Deep</title></head><body>` +
+		strings.Repeat(`<div>`, depth) + `<meta name="deep" content="found">` + strings.Repeat(`</div>`, depth) +
+		`</body></html>`
+	res := parseString(t, doc)
+	if res.Title != "Deep" {
+		t.Errorf("Title = %q", res.Title)
+	}
+	if got := res.AsMap(FirstWins)["deep"]; got != "found" {
+		t.Errorf("meta tag at depth %d: got %q", depth, got)
+	}
+}
+
+// countingVisitor counts the elements it visits.
+type countingVisitor struct{ n int }
+
+func (v *countingVisitor) Visit(*html.Node) { v.n++ }
+
+func TestVisitDeepTree(t *testing.T) {
+	const depth = 1000000
+	root := &html.Node{Type: html.ElementNode, Data: "div"}
+	for n, i := root, 1; i < depth; i++ {
+		child := &html.Node{Type: html.ElementNode, Data: "div"}
+		n.AppendChild(child)
+		n = child
+	}
+	v := &countingVisitor{}
+	visit(root, []TagVisitor{v})
+	if v.n != depth {
+		t.Errorf("visited %d elements, want %d", v.n, depth)
+	}
+}
+
+func TestTagOrderMatchesAcrossParsers(t *testing.T) {
+	const doc = `<!DOCTYPE html><html><head>
+<meta charset="utf-8">
+<meta name="z-first" content="1">
+<meta property="og:title" content="2">
+<meta http-equiv="X-UA-Compatible" content="3">
+<title>Order</title>
+<meta name="a-fourth" content="4">
+<noscript><meta name="in-noscript" content="5"></noscript>
+<meta property="og:title" content="6">
+<meta name="description" content="7">
+</head><body></body></html>`
+	want := []string{"z-first", "og:title", "X-UA-Compatible", "a-fourth", "in-noscript", "og:title", "description"}
+	names := func(tags []MetaTag) []string {
+		var names []string
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		return names
+	}
+	e := New()
+	dom, err := e.Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := e.ParseHead(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed []MetaTag
+	err = e.ExtractFunc(strings.NewReader(doc), func(tag MetaTag) bool {
+		streamed = append(streamed, tag)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for method, tags := range map[string][]MetaTag{"Parse": dom.Tags, "ParseHead": head.Tags, "ExtractFunc": streamed} {
+		if got := names(tags); !slices.Equal(got, want) {
+			t.Errorf("%s order = %q, want %q", method, got, want)
+		}
+	}
+}
+
+func TestNoscriptMeta(t *testing.T) {
+	const doc = `<html><head>
+<noscript><meta name="p:domain_verify" content="abc123"><img src="/pixel.gif"></noscript>
+<meta name="after" content="x">
+</head><body><noscript><meta name="in-body" content="y"></noscript></body></html>`
+	for method, parse := range map[string]func(*Extractor) (*Result, error){
+		"Parse":     func(e *Extractor) (*Result, error) { return e.Parse(strings.NewReader(doc)) },
+		"ParseHead": func(e *Extractor) (*Result, error) { return e.ParseHead(strings.NewReader(doc)) },
+	} {
+		res, err := parse(New())
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if got := res.AsMap(FirstWins)["p:domain_verify"]; got != "abc123" {
+			t.Errorf("%s: meta inside <noscript> in head: got %q", method, got)
+		}
+		if got := res.AsMap(FirstWins)["after"]; got != "x" {
+			t.Errorf("%s: meta after <noscript>: got %q", method, got)
+		}
+	}
+	res := parseString(t, doc)
+	if got := res.AsMap(FirstWins)["in-body"]; got != "y" {
+		t.Errorf("meta inside <noscript> in body: got %q", got)
+	}
+}
+
+func TestEmptyPropertyContent(t *testing.T) {
+	for _, content := range []string{"", "   ", "\t\n"} {
+		doc := `<meta property="og:image" content="` + content + `"><meta property="og:site_name" content="Example">`
+		dropped := parseString(t, doc)
+		if len(dropped.Tags) != 1 || dropped.Tags[0].Name != "og:site_name" {
+			t.Errorf("content %q: got %v, want only og:site_name", content, dropped.Tags)
+		}
+		if len(dropped.Warnings) != 1 || dropped.Warnings[0] != "og:image has no content" {
+			t.Errorf("content %q: Warnings = %q", content, dropped.Warnings)
+		}
+
+		kept := parseString(t, doc, WithKeepEmptyContent(true))
+		if len(kept.Tags) != 2 {
+			t.Fatalf("content %q: got %v, want both tags kept", content, kept.Tags)
+		}
+		want := MetaTag{Name: "og:image", Property: "og:image", Source: SourceProperty}
+		if kept.Tags[0] != want {
+			t.Errorf("content %q: got %+v, want %+v", content, kept.Tags[0], want)
+		}
+		if og := ParseOpenGraph(kept.Tags); len(og.Images) != 0 || og.SiteName != "Example" {
+			t.Errorf("content %q: Images = %q, SiteName = %q", content, og.Images, og.SiteName)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package htmlmetadata
+
+import "strings"
+
+// TitleElement stands for the document's <title> element in a title
+// priority order.
+const TitleElement = "<title>"
+
+// Default priority orders used by BestTitle, BestDescription and BestImage.
+// Each entry is a meta name or property, matched case-insensitively.
+var (
+	DefaultTitleOrder       = []string{"og:title", TitleElement, "twitter:title"}
+	DefaultDescriptionOrder = []string{"og:description", "description", "twitter:description"}
+	DefaultImageOrder       = []string{"og:image", "og:image:url", "twitter:image", "twitter:image:src"}
+)
+
+// BestTitle returns the first non-empty title found by walking order, or
+// DefaultTitleOrder if order is empty. TitleElement selects the <title>
+// element.
+func (r *Result) BestTitle(order ...string) string {
+	if len(order) == 0 {
+		order = DefaultTitleOrder
+	}
+	return r.best(order)
+}
+
+// BestDescription returns the first non-empty description found by walking
+// order, or DefaultDescriptionOrder if order is empty.
+func (r *Result) BestDescription(order ...string) string {
+	if len(order) == 0 {
+		order = DefaultDescriptionOrder
+	}
+	return r.best(order)
+}
+
+// BestImage returns the first non-empty image URL found by walking order, or
+// DefaultImageOrder if order is empty.
+func (r *Result) BestImage(order ...string) string {
+	if len(order) == 0 {
+		order = DefaultImageOrder
+	}
+	return r.best(order)
+}
+
+// best returns the first non-empty value for the names in order.
+func (r *Result) best(order []string) string {
+	for _, name := range order {
+		if name == TitleElement {
+			if r.Title != "" {
+				return r.Title
+			}
+			continue
+		}
+		for _, tag := range r.Tags {
+			if strings.EqualFold(tag.Name, name) && strings.TrimSpace(tag.Content) != "" {
+				return tag.Content
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package htmlmetadata
+
+import (
+	"context"
+	"net/http"
+)
+
+// ProbeResult describes a URL checked by Probe.
+type ProbeResult struct {
+	// URL is the final URL after redirects.
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	// ContentType is the Content-Type header, as sent.
+	ContentType string `json:"content_type,omitempty"`
+	// ContentLength is the declared body size, or -1 if it is unknown.
+	ContentLength int64 `json:"content_length"`
+	// Location is the absolute redirect target of a 3xx response that was not
+	// followed.
+	Location string `json:"location,omitempty"`
+	// IsHTML reports that the content type is one the Extractor would parse.
+	IsHTML bool `json:"is_html"`
+}
+
+// Probe checks whether rawURL is reachable and serves a page, without
+// downloading or parsing the body. It sends a HEAD request, falling back to
+// GET for servers that reject HEAD, and follows redirects, retries and
+// headers as configured. Any status is reported in the result rather than as
+// an error; only an unusable URL or a failed request returns an error.
+func (e *Extractor) Probe(ctx context.Context, rawURL string) (*ProbeResult, error) {
+	if err := e.validateURL(rawURL); err != nil {
+		return nil, err
+	}
+	if err := e.checkRobotsURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	resp, err := e.probe(ctx, http.MethodHead, rawURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = e.probe(ctx, http.MethodGet, rawURL)
+	} else if err != nil && ctx.Err() == nil {
+		resp, err = e.probe(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The body is closed unread; for GET this costs the connection, which is
+	// cheaper than draining a page nobody wants
+	resp.Body.Close()
+
+	meta := responseResult(resp)
+	res := &ProbeResult{
+		URL:           meta.URL,
+		StatusCode:    resp.StatusCode,
+		ContentType:   meta.ContentType,
+		ContentLength: resp.ContentLength,
+		IsHTML:        meta.ContentType != "" && e.acceptsContentType(meta.ContentType),
+	}
+	if e.stoppedAtRedirect(resp.StatusCode) {
+		if loc, err := resp.Location(); err == nil {
+			res.Location = loc.String()
+		}
+	}
+	return res, nil
+}
+
+// probe sends a bodiless request for rawURL.
+func (e *Extractor) probe(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := e.newRequest(ctx, method, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return e.do(ctx, req)
+}
@@ -0,0 +1,53 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// WithProxy sends requests through the proxy at proxyURL. http:// and
+// https:// URLs name an HTTP proxy; socks5:// and socks5h:// name a SOCKS5
+// proxy, such as Tor's on 127.0.0.1:9050. Credentials may be given in the
+// URL's user info.
+//
+// The proxy is installed on a copy of the transport from WithTransport, which
+// must then be an *http.Transport, or of http.DefaultTransport. .i2p hosts
+// configured with WithI2P keep using their own transport. An invalid proxy URL
+// makes every request fail with an error describing it.
+func WithProxy(proxyURL string) Option {
+	return func(e *Extractor) {
+		e.proxyURL = proxyURL
+	}
+}
+
+// setProxy makes t dial through the proxy at rawProxy. A SOCKS5 proxy is
+// reached with forward.
+func setProxy(t *http.Transport, rawProxy string, forward *net.Dialer) error {
+	u, err := url.Parse(rawProxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		t.Proxy = http.ProxyURL(u)
+	case "socks5", "socks5h":
+		dialer, err := proxy.FromURL(u, forward)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		cd, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return fmt.Errorf("proxy %s does not support contexts", u.Redacted())
+		}
+		t.Proxy = nil
+		t.DialContext = cd.DialContext
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package htmlmetadata
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHTTPProxy(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target
+		mu.Lock()
+		proxied = append(proxied, r.URL.String())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Via proxy</title>"))
+	}))
+	defer proxy.Close()
+
+	res, err := New(WithProxy(proxy.URL)).ExtractResponse("http://example.invalid/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Title != "Via proxy" || len(proxied) != 1 || proxied[0] != "http://example.invalid/page" {
+		t.Errorf("Title = %q, proxy saw %q", res.Title, proxied)
+	}
+}
+
+// socks5Server accepts SOCKS5 CONNECT requests without authentication and
+// connects each one to target, whatever address was asked for. It returns
+// its address and a function listing the addresses requested.
+func socks5Server(t *testing.T, target string) (string, func() []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	var mu sync.Mutex
+	var requested []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				addr, err := socks5Handshake(conn)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				requested = append(requested, addr)
+				mu.Unlock()
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+// socks5Handshake reads a greeting and a CONNECT request from conn, accepts
+// both, and returns the requested address.
+func socks5Handshake(conn net.Conn) (string, error) {
+	buf := make([]byte, 262)
+	// Version, method count and methods
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+	// Version, command, reserved and address type
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return "", err
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+			return "", err
+		}
+		host = net.IP(buf[:4]).String()
+	case 3:
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return "", err
+		}
+		n := buf[0]
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return "", err
+		}
+		host = string(buf[:n])
+	default:
+		return "", io.ErrUnexpectedEOF
+	}
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	port := binary.BigEndian.Uint16(buf[:2])
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Via SOCKS5 " + r.Host + "</title>"))
+	}))
+	defer srv.Close()
+	for _, scheme := range []string{"socks5", "socks5h"} {
+		addr, requested := socks5Server(t, srv.Listener.Addr().String())
+		res, err := New(WithProxy(scheme + "://" + addr)).ExtractResponse("http://example.invalid:8080/")
+		if err != nil {
+			t.Fatalf("%s: %v", scheme, err)
+		}
+		if res.Title != "Via SOCKS5 example.invalid:8080" {
+			t.Errorf("%s: Title = %q", scheme, res.Title)
+		}
+		// The proxy resolves the name, so it is sent unresolved
+		if got := requested(); len(got) != 1 || got[0] != "example.invalid:8080" {
+			t.Errorf("%s: proxy asked for %q", scheme, got)
+		}
+	}
+}
+
+func TestInvalidProxy(t *testing.T) {
+	tests := []struct {
+		proxy, want string
+	}{
+		{"ftp://proxy.example:21", `unsupported proxy scheme "ftp"`},
+		{"://missing-scheme", "invalid proxy URL"},
+	}
+	for _, tt := range tests {
+		_, err := New(WithProxy(tt.proxy)).ExtractResponse("http://example.invalid/")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.proxy, err, tt.want)
+		}
+	}
+}
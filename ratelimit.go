@@ -0,0 +1,55 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit throttles requests to each host to perHost requests per
+// second, with bursts of up to burst requests; a burst below 1 is taken as 1,
+// since no request could ever pass otherwise. Hosts are limited
+// independently, so requests to unrelated hosts proceed in parallel, and
+// every request counts, including redirects, retries, preflights and
+// robots.txt fetches. A request waiting for its turn gives up when its
+// context ends.
+//
+// One limiter is kept per host, for as many hosts as WithHostCacheSize
+// allows.
+func WithRateLimit(perHost rate.Limit, burst int) Option {
+	return func(e *Extractor) {
+		e.rateLimit = &hostLimiter{limit: perHost, burst: max(burst, 1)}
+	}
+}
+
+// hostLimiter holds a rate.Limiter per hostname. New sets hosts.
+type hostLimiter struct {
+	limit rate.Limit
+	burst int
+	hosts *hostCache[*rate.Limiter]
+}
+
+func (l *hostLimiter) get(host string) *rate.Limiter {
+	return l.hosts.get(strings.ToLower(host), func() *rate.Limiter {
+		return rate.NewLimiter(l.limit, l.burst)
+	})
+}
+
+// rateLimitTransport waits for the host's limiter before each request.
+type rateLimitTransport struct {
+	limiter *hostLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if err := t.limiter.get(host).Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("rate limit for %s: %w", host, err)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,20 @@
+package htmlmetadata
+
+import (
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitNonPositiveBurst(t *testing.T) {
+	for _, burst := range []int{0, -1} {
+		e := New(WithRateLimit(rate.Limit(100), burst), WithTransport(NewStaticTransport(map[string]string{
+			"https://example.com/": `<title>Limited</title>`,
+		})))
+		for i := 0; i < 2; i++ {
+			if _, err := e.ExtractResponse("https://example.com/"); err != nil {
+				t.Fatalf("burst %d, request %d: %v", burst, i, err)
+			}
+		}
+	}
+}
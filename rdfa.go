@@ -0,0 +1,208 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Triple is an RDF statement extracted from RDFa markup.
+type Triple struct {
+	// Subject is an IRI, or a blank node such as "_:b0".
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	// Object is an IRI or, when Literal is set, a plain text value.
+	Object  string `json:"object"`
+	Literal bool   `json:"literal,omitempty"`
+}
+
+// rdfType is the predicate emitted for typeof.
+const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+
+// rdfaPrefixes is the subset of the RDFa initial context seen in practice.
+var rdfaPrefixes = map[string]string{
+	"og":      "http://ogp.me/ns#",
+	"dc":      "http://purl.org/dc/terms/",
+	"dcterms": "http://purl.org/dc/terms/",
+	"foaf":    "http://xmlns.com/foaf/0.1/",
+	"rdf":     "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":    "http://www.w3.org/2000/01/rdf-schema#",
+	"schema":  "http://schema.org/",
+	"xsd":     "http://www.w3.org/2001/XMLSchema#",
+}
+
+// rdfaContext is the evaluation context inherited by an element's children.
+type rdfaContext struct {
+	vocab    string
+	prefixes map[string]string
+	subject  string
+}
+
+// extractRDFa returns the triples expressed by RDFa Lite attributes (vocab,
+// prefix, typeof, property and resource) in doc. This is RDFa Lite only: the
+// full RDFa 1.1 processing rules, such as rel/rev chaining, are not applied.
+// Relative IRIs are resolved against base, which is also the initial subject.
+func extractRDFa(doc *html.Node, base *url.URL) []Triple {
+	p := &rdfaProcessor{base: base}
+	initial := rdfaContext{prefixes: rdfaPrefixes}
+	if base != nil {
+		initial.subject = base.String()
+	}
+	p.walk(doc, initial)
+	return p.triples
+}
+
+type rdfaProcessor struct {
+	base    *url.URL
+	triples []Triple
+	blanks  int
+}
+
+// walk processes n and its descendants in document order, each element in
+// the context its parent established.
+func (p *rdfaProcessor) walk(n *html.Node, ctx rdfaContext) {
+	type frame struct {
+		n   *html.Node
+		ctx rdfaContext
+	}
+	stack := []frame{{n, ctx}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.n.Type == html.ElementNode {
+			f.ctx = p.element(f.n, f.ctx)
+		}
+		for child := f.n.LastChild; child != nil; child = child.PrevSibling {
+			stack = append(stack, frame{child, f.ctx})
+		}
+	}
+}
+
+// element emits the triples of n and returns the context for its children.
+func (p *rdfaProcessor) element(n *html.Node, ctx rdfaContext) rdfaContext {
+	if hasAttr(n, "vocab") {
+		ctx.vocab = strings.TrimSpace(attrValue(n.Attr, "vocab"))
+	}
+	if prefix := attrValue(n.Attr, "prefix"); prefix != "" {
+		ctx.prefixes = withPrefixes(ctx.prefixes, prefix)
+	}
+
+	properties := p.expandAll(attrValue(n.Attr, "property"), ctx)
+	resource, hasResource := "", hasAttr(n, "resource")
+	if hasResource {
+		resource = p.expandIRI(attrValue(n.Attr, "resource"), ctx)
+	}
+
+	if hasAttr(n, "typeof") {
+		subject := resource
+		if !hasResource {
+			subject = p.blank()
+		}
+		for _, t := range p.expandAll(attrValue(n.Attr, "typeof"), ctx) {
+			p.triples = append(p.triples, Triple{Subject: subject, Predicate: rdfType, Object: t})
+		}
+		for _, prop := range properties {
+			p.triples = append(p.triples, Triple{Subject: ctx.subject, Predicate: prop, Object: subject})
+		}
+		ctx.subject = subject
+		return ctx
+	}
+
+	if len(properties) == 0 {
+		if hasResource {
+			ctx.subject = resource
+		}
+		return ctx
+	}
+	object, literal := p.object(n, resource, hasResource)
+	for _, prop := range properties {
+		p.triples = append(p.triples, Triple{Subject: ctx.subject, Predicate: prop, Object: object, Literal: literal})
+	}
+	return ctx
+}
+
+// object returns the value of a property on n, and whether it is a literal.
+func (p *rdfaProcessor) object(n *html.Node, resource string, hasResource bool) (string, bool) {
+	switch {
+	case hasResource:
+		return resource, false
+	case hasAttr(n, "content"):
+		return attrValue(n.Attr, "content"), true
+	case hasAttr(n, "href"):
+		return resolveURL(p.base, strings.TrimSpace(attrValue(n.Attr, "href"))), false
+	case hasAttr(n, "src"):
+		return resolveURL(p.base, strings.TrimSpace(attrValue(n.Attr, "src"))), false
+	case n.DataAtom == atom.Time && hasAttr(n, "datetime"):
+		return attrValue(n.Attr, "datetime"), true
+	}
+	return textContent(n), true
+}
+
+// expandAll expands each space-separated term in list.
+func (p *rdfaProcessor) expandAll(list string, ctx rdfaContext) []string {
+	var out []string
+	for _, term := range strings.Fields(list) {
+		out = append(out, p.expandTerm(term, ctx))
+	}
+	return out
+}
+
+// expandTerm expands a property or type: a CURIE with a known prefix, an
+// absolute IRI, or a term relative to the vocabulary.
+func (p *rdfaProcessor) expandTerm(term string, ctx rdfaContext) string {
+	if prefix, local, ok := strings.Cut(term, ":"); ok {
+		if ns, known := ctx.prefixes[strings.ToLower(prefix)]; known {
+			return ns + local
+		}
+		return term
+	}
+	if ctx.vocab != "" {
+		return ctx.vocab + term
+	}
+	return term
+}
+
+// expandIRI expands a resource value: a safe or plain CURIE with a known
+// prefix, or a possibly relative IRI.
+func (p *rdfaProcessor) expandIRI(value string, ctx rdfaContext) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+	if prefix, local, ok := strings.Cut(value, ":"); ok {
+		if ns, known := ctx.prefixes[strings.ToLower(prefix)]; known {
+			return ns + local
+		}
+	}
+	return resolveURL(p.base, value)
+}
+
+// blank returns a fresh blank node identifier.
+func (p *rdfaProcessor) blank() string {
+	id := fmt.Sprintf("_:b%d", p.blanks)
+	p.blanks++
+	return id
+}
+
+// withPrefixes returns a copy of prefixes extended by a prefix attribute such
+// as "og: http://ogp.me/ns# dc: http://purl.org/dc/terms/".
+func withPrefixes(prefixes map[string]string, attr string) map[string]string {
+	out := make(map[string]string, len(prefixes))
+	for k, v := range prefixes {
+		out[k] = v
+	}
+	fields := strings.Fields(attr)
+	for i := 0; i+1 < len(fields); i += 2 {
+		name := strings.TrimSuffix(fields[i], ":")
+		if name == fields[i] {
+			// Not a "name:" token; resynchronize on the next one
+			i--
+			continue
+		}
+		out[strings.ToLower(name)] = fields[i+1]
+	}
+	return out
+}
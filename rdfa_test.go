@@ -0,0 +1,117 @@
+package htmlmetadata
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// hasTriple reports whether triples contains want.
+func hasTriple(triples []Triple, want Triple) bool {
+	for _, t := range triples {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+// The fixtures follow the RDFa examples on schema.org.
+
+func TestRDFaSchemaOrgPerson(t *testing.T) {
+	const doc = `<html><body>
+<div vocab="https://schema.org/" typeof="Person">
+  <span property="name">Jane Doe</span>
+  <img src="janedoe.jpg" property="image" alt="Photo of Jane Doe"/>
+  <span property="jobTitle">Professor</span>
+  <div property="address" typeof="PostalAddress">
+    <span property="streetAddress">20341 Whitworth Institute 405 N. Whitworth</span>
+    <span property="addressLocality">Seattle</span>,
+    <span property="addressRegion">WA</span>
+    <span property="postalCode">98052</span>
+  </div>
+  <span property="telephone">(425) 123-4567</span>
+  <a href="mailto:jane-doe@xyz.edu" property="email">jane-doe@xyz.edu</a>
+  Jane's home page: <a href="http://www.janedoe.com/" property="url">janedoe.com</a>
+</div>
+</body></html>`
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return htmlResponse(req, "text/html", doc), nil
+	})
+	e := New(WithTransport(transport))
+	res, err := e.ExtractResponse("https://example.com/people/jane")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	const s = "https://schema.org/"
+	for _, want := range []Triple{
+		{Subject: "_:b0", Predicate: rdfType, Object: s + "Person"},
+		{Subject: "_:b0", Predicate: s + "name", Object: "Jane Doe", Literal: true},
+		{Subject: "_:b0", Predicate: s + "image", Object: "https://example.com/people/janedoe.jpg"},
+		{Subject: "_:b0", Predicate: s + "jobTitle", Object: "Professor", Literal: true},
+		{Subject: "_:b0", Predicate: s + "address", Object: "_:b1"},
+		{Subject: "_:b1", Predicate: rdfType, Object: s + "PostalAddress"},
+		{Subject: "_:b1", Predicate: s + "addressLocality", Object: "Seattle", Literal: true},
+		{Subject: "_:b1", Predicate: s + "postalCode", Object: "98052", Literal: true},
+		{Subject: "_:b0", Predicate: s + "telephone", Object: "(425) 123-4567", Literal: true},
+		{Subject: "_:b0", Predicate: s + "email", Object: "mailto:jane-doe@xyz.edu"},
+		{Subject: "_:b0", Predicate: s + "url", Object: "http://www.janedoe.com/"},
+	} {
+		if !hasTriple(res.RDFa, want) {
+			t.Errorf("missing %+v in\n%s", want, formatTriples(res.RDFa))
+		}
+	}
+	// Nested properties belong to the address, not the person
+	if hasTriple(res.RDFa, Triple{Subject: "_:b0", Predicate: s + "postalCode", Object: "98052", Literal: true}) {
+		t.Error("postalCode attached to the Person")
+	}
+}
+
+func TestRDFaSchemaOrgProductWithPrefixAndResource(t *testing.T) {
+	const doc = `<html><body>
+<div prefix="s: http://schema.org/ ex: https://example.com/terms#" typeof="s:Product" resource="#widget">
+  <span property="s:name">Example Widget</span>
+  <meta property="s:sku" content="W-42"/>
+  <span property="ex:colour">Blue</span>
+  <div property="s:offers" typeof="s:Offer">
+    <meta property="s:priceCurrency" content="USD"/>
+    <span property="s:price" content="19.99">$19.99</span>
+    <link property="s:availability" href="http://schema.org/InStock"/>
+  </div>
+  <a property="s:manufacturer" resource="s:Organization" href="/acme">ACME</a>
+</div>
+</body></html>`
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return htmlResponse(req, "text/html", doc), nil
+	})
+	e := New(WithTransport(transport))
+	res, err := e.ExtractResponse("https://example.com/shop/")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	const s, product = "http://schema.org/", "https://example.com/shop/#widget"
+	for _, want := range []Triple{
+		{Subject: product, Predicate: rdfType, Object: s + "Product"},
+		{Subject: product, Predicate: s + "name", Object: "Example Widget", Literal: true},
+		{Subject: product, Predicate: s + "sku", Object: "W-42", Literal: true},
+		{Subject: product, Predicate: "https://example.com/terms#colour", Object: "Blue", Literal: true},
+		{Subject: product, Predicate: s + "offers", Object: "_:b0"},
+		{Subject: "_:b0", Predicate: rdfType, Object: s + "Offer"},
+		{Subject: "_:b0", Predicate: s + "price", Object: "19.99", Literal: true},
+		{Subject: "_:b0", Predicate: s + "availability", Object: s + "InStock"},
+		{Subject: product, Predicate: s + "manufacturer", Object: s + "Organization"},
+	} {
+		if !hasTriple(res.RDFa, want) {
+			t.Errorf("missing %+v in\n%s", want, formatTriples(res.RDFa))
+		}
+	}
+}
+
+// formatTriples lists triples one per line, for failure messages.
+func formatTriples(triples []Triple) string {
+	var b strings.Builder
+	for _, t := range triples {
+		b.WriteString(t.Subject + " " + t.Predicate + " " + t.Object + "\n")
+	}
+	return b.String()
+}
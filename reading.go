@@ -0,0 +1,70 @@
+package htmlmetadata
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultWordsPerMinute is the reading speed used by WithReadingMetrics when
+// none is given, a typical figure for adults reading prose on screen.
+const DefaultWordsPerMinute = 230
+
+// boilerplate lists elements whose text is not part of the main content.
+var boilerplate = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+}
+
+// countWords returns the number of whitespace-separated words in the text of
+// the <body> of doc, skipping boilerplate elements such as navigation and
+// scripts. It returns 0 if doc has no body.
+func countWords(doc *html.Node) int {
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		return 0
+	}
+	words := 0
+	stack := []*html.Node{body}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch n.Type {
+		case html.TextNode:
+			words += len(strings.Fields(n.Data))
+			continue
+		case html.ElementNode:
+			if boilerplate[n.DataAtom] || n.Namespace != "" {
+				continue
+			}
+		}
+		stack = pushChildren(stack, n)
+	}
+	return words
+}
+
+// findElement returns the first element beneath n, in document order, with
+// the given atom, or nil.
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	stack := pushChildren(nil, n)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		if n.Type == html.ElementNode && n.DataAtom == a {
+			return n
+		}
+		stack = pushChildren(stack[:len(stack)-1], n)
+	}
+	return nil
+}
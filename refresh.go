@@ -0,0 +1,71 @@
+package htmlmetadata
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MetaRefresh is a parsed <meta http-equiv="refresh"> directive.
+type MetaRefresh struct {
+	// Seconds is the delay before the refresh.
+	Seconds int `json:"seconds"`
+	// URL is the redirect target, resolved against the document base. It is
+	// empty when the page merely reloads itself.
+	URL string `json:"url,omitempty"`
+}
+
+// parseRefresh parses the content of a refresh directive such as
+// "5; url=https://example.com/" following the HTML shared declarative refresh
+// steps. It returns nil if content does not start with a delay.
+func parseRefresh(content string) *MetaRefresh {
+	s := strings.TrimLeft(content, asciiSpace)
+
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	digits := s[:end]
+	// Fractional seconds are permitted but ignored
+	for end < len(s) && (s[end] == '.' || (s[end] >= '0' && s[end] <= '9')) {
+		end++
+	}
+	if end == 0 {
+		return nil
+	}
+	seconds := 0
+	if digits != "" {
+		if n, err := strconv.Atoi(digits); err == nil {
+			seconds = n
+		}
+	}
+	refresh := &MetaRefresh{Seconds: seconds}
+
+	s = strings.TrimLeft(s[end:], asciiSpace)
+	if s == "" {
+		return refresh
+	}
+	if s[0] != ';' && s[0] != ',' {
+		return nil
+	}
+	s = strings.TrimLeft(s[1:], asciiSpace)
+
+	// An optional "url =" prefix, case-insensitive
+	if len(s) >= 3 && strings.EqualFold(s[:3], "url") {
+		if rest := strings.TrimLeft(s[3:], asciiSpace); strings.HasPrefix(rest, "=") {
+			s = strings.TrimLeft(rest[1:], asciiSpace)
+		}
+	}
+
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		quote := s[0]
+		s = s[1:]
+		if i := strings.IndexByte(s, quote); i >= 0 {
+			s = s[:i]
+		}
+	}
+	refresh.URL = strings.TrimRight(s, asciiSpace)
+	return refresh
+}
+
+// asciiSpace is the set of ASCII whitespace characters defined by HTML.
+const asciiSpace = " \t\n\f\r"
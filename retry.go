@@ -0,0 +1,88 @@
+package htmlmetadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryStatus lists the status codes retried unless WithRetryStatus
+// overrides them.
+var DefaultRetryStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultBackoff waits 500ms before the first retry and doubles the delay for
+// each one after.
+func DefaultBackoff(attempt int) time.Duration {
+	return 500 * time.Millisecond << (attempt - 1)
+}
+
+// shouldRetry reports whether a response with the given status is retried.
+func (e *Extractor) shouldRetry(code int) bool {
+	for _, c := range e.retryStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before retry number attempt. A
+// Retry-After header on resp, if any, takes precedence over the backoff.
+func (e *Extractor) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+	if e.backoff != nil {
+		return e.backoff(attempt)
+	}
+	return DefaultBackoff(attempt)
+}
+
+// parseRetryAfter parses a Retry-After value given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discard drains and closes a response body so its connection can be reused.
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
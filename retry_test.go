@@ -0,0 +1,181 @@
+package htmlmetadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noBackoff retries immediately.
+func noBackoff(int) time.Duration { return 0 }
+
+// hourBackoff waits long enough that a test only finishes if something else,
+// such as Retry-After or a canceled context, cuts the wait short.
+func hourBackoff(int) time.Duration { return time.Hour }
+
+// flakyServer answers the first failures requests with status and the rest
+// with a page, counting every request.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Recovered</title>"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRetryStatuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		retryStatus  []int
+		wantRequests int32
+		wantCode     int
+	}{
+		{"503 retried", http.StatusServiceUnavailable, nil, 3, 0},
+		{"502 retried", http.StatusBadGateway, nil, 3, 0},
+		{"404 not retried", http.StatusNotFound, nil, 1, http.StatusNotFound},
+		{"custom status retried", http.StatusTeapot, []int{http.StatusTeapot}, 3, 0},
+		{"default status dropped", http.StatusServiceUnavailable, []int{http.StatusTeapot}, 1, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		srv, requests := flakyServer(t, 2, tt.status, nil)
+		opts := []Option{WithRetry(3, noBackoff)}
+		if tt.retryStatus != nil {
+			opts = append(opts, WithRetryStatus(tt.retryStatus...))
+		}
+		res, err := New(opts...).ExtractResponse(srv.URL)
+		if got := requests.Load(); got != tt.wantRequests {
+			t.Errorf("%s: %d requests, want %d", tt.name, got, tt.wantRequests)
+		}
+		var statusErr *StatusError
+		switch {
+		case tt.wantCode == 0 && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.wantCode == 0 && res.Title != "Recovered":
+			t.Errorf("%s: Title = %q", tt.name, res.Title)
+		case tt.wantCode != 0 && (!errors.As(err, &statusErr) || statusErr.Code != tt.wantCode):
+			t.Errorf("%s: err = %v, want status %d", tt.name, err, tt.wantCode)
+		}
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv, requests := flakyServer(t, 5, http.StatusServiceUnavailable, nil)
+	_, err := New(WithRetry(2, noBackoff)).ExtractResponse(srv.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the last 503", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("%d requests, want 2", got)
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			// Drop the connection without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Recovered</title>"))
+	}))
+	defer srv.Close()
+
+	if _, err := New().ExtractResponse(srv.URL); err == nil {
+		t.Fatal("a dropped connection succeeded without retries")
+	}
+	requests.Store(0)
+	res, err := New(WithRetry(3, noBackoff)).ExtractResponse(srv.URL)
+	if err != nil {
+		t.Fatalf("with retries: %v", err)
+	}
+	if res.Title != "Recovered" || requests.Load() != 3 {
+		t.Errorf("Title = %q after %d requests, want the third to succeed", res.Title, requests.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name, retryAfter string
+		status           int
+	}{
+		{"seconds on 429", "0", http.StatusTooManyRequests},
+		{"HTTP date on 503", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		srv, requests := flakyServer(t, 1, tt.status, http.Header{"Retry-After": {tt.retryAfter}})
+		// The backoff would outlast the deadline; only Retry-After is short
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		res, err := New(WithRetry(2, hourBackoff)).ExtractResponseContext(ctx, srv.URL)
+		cancel()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if res.Title != "Recovered" || requests.Load() != 2 {
+			t.Errorf("%s: Title = %q after %d requests", tt.name, res.Title, requests.Load())
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		value    string
+		min, max time.Duration
+		ok       bool
+	}{
+		{"120", 120 * time.Second, 120 * time.Second, true},
+		{"0", 0, 0, true},
+		{now.Add(time.Hour).UTC().Format(http.TimeFormat), 58 * time.Minute, time.Hour, true},
+		{now.Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0, true},
+		{"", 0, 0, false},
+		{"-5", 0, 0, false},
+		{"soon", 0, 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := parseRetryAfter(tt.value)
+		if ok != tt.ok || d < tt.min || d > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v in [%v, %v]", tt.value, d, ok, tt.ok, tt.min, tt.max)
+		}
+	}
+}
+
+func TestRetryStopsWhenContextCanceled(t *testing.T) {
+	srv, requests := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	e := New(WithRetry(5, hourBackoff), WithHooks(Hooks{
+		// Cancel while the Extractor waits for the second attempt
+		OnFetchDone: func(string, int, time.Duration, error) { cancel() },
+	}))
+	start := time.Now()
+	_, err := e.ExtractResponseContext(ctx, srv.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v to notice the cancellation", elapsed)
+	}
+}
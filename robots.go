@@ -0,0 +1,150 @@
+package htmlmetadata
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RobotsDirectives holds the indexing directives of a page.
+type RobotsDirectives struct {
+	NoIndex      bool `json:"no_index"`
+	NoFollow     bool `json:"no_follow"`
+	NoArchive    bool `json:"no_archive"`
+	NoSnippet    bool `json:"no_snippet"`
+	NoImageIndex bool `json:"no_image_index"`
+	NoTranslate  bool `json:"no_translate"`
+	// MaxSnippet and MaxVideoPreview are -1 when unset or unlimited.
+	MaxSnippet      int `json:"max_snippet"`
+	MaxVideoPreview int `json:"max_video_preview"`
+	// MaxImagePreview is "none", "standard" or "large", or "" when unset.
+	MaxImagePreview string `json:"max_image_preview,omitempty"`
+	// Directives lists every directive token seen, lowercased, in order.
+	Directives []string `json:"directives,omitempty"`
+}
+
+// ParseRobots reads the name="robots" tags, plus tags named after any of the
+// given crawler agents such as "googlebot", and combines their directives.
+// Matching and parsing are case-insensitive. When several tags apply, the
+// most restrictive value of each directive wins. Result.Robots adds the
+// X-Robots-Tag response headers.
+func ParseRobots(tags []MetaTag, agents ...string) RobotsDirectives {
+	rd := RobotsDirectives{MaxSnippet: -1, MaxVideoPreview: -1}
+	for _, tag := range tags {
+		if tag.Source != SourceName || !robotsTagApplies(tag.Name, agents) {
+			continue
+		}
+		rd.add(tag.Content)
+	}
+	return rd
+}
+
+// Robots combines the directives of the page's robots meta tags, as read by
+// ParseRobots, with those of its X-Robots-Tag response headers. As for
+// search engines, the union applies: a directive given by either source
+// counts, and of two limits the more restrictive wins. Header values
+// addressed to a crawler, as in "X-Robots-Tag: googlebot: noindex", only
+// apply if the crawler is one of agents.
+func (r *Result) Robots(agents ...string) RobotsDirectives {
+	rd := ParseRobots(r.Tags, agents...)
+	for _, v := range r.XRobotsTag {
+		if agent, directives, ok := strings.Cut(v, ":"); ok && isRobotsAgent(agent) {
+			if !robotsTagApplies(strings.TrimSpace(agent), agents) {
+				continue
+			}
+			v = directives
+		}
+		rd.add(v)
+	}
+	return rd
+}
+
+// valuedRobotsDirectives are the directives written as "name: value", which
+// an X-Robots-Tag value starting with a crawler name must not be taken for.
+var valuedRobotsDirectives = []string{"max-snippet", "max-video-preview", "max-image-preview", "unavailable_after"}
+
+// isRobotsAgent reports whether the text before the first colon of an
+// X-Robots-Tag value is a crawler name rather than a directive.
+func isRobotsAgent(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s != "" && !strings.ContainsAny(s, ", ") && !slices.Contains(valuedRobotsDirectives, s)
+}
+
+// robotsTagApplies reports whether a meta tag called name carries robots
+// directives for one of agents.
+func robotsTagApplies(name string, agents []string) bool {
+	if strings.EqualFold(name, "robots") {
+		return true
+	}
+	for _, agent := range agents {
+		if strings.EqualFold(name, agent) {
+			return true
+		}
+	}
+	return false
+}
+
+// add merges a comma-separated directive list into rd.
+func (rd *RobotsDirectives) add(content string) {
+	for _, token := range strings.Split(content, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" {
+			continue
+		}
+		rd.Directives = append(rd.Directives, token)
+
+		key, value, _ := strings.Cut(token, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "noindex":
+			rd.NoIndex = true
+		case "nofollow":
+			rd.NoFollow = true
+		case "none":
+			rd.NoIndex, rd.NoFollow = true, true
+		case "noarchive", "nocache":
+			rd.NoArchive = true
+		case "nosnippet":
+			rd.NoSnippet = true
+		case "noimageindex":
+			rd.NoImageIndex = true
+		case "notranslate":
+			rd.NoTranslate = true
+		case "max-snippet":
+			rd.MaxSnippet = minLimit(rd.MaxSnippet, value)
+		case "max-video-preview":
+			rd.MaxVideoPreview = minLimit(rd.MaxVideoPreview, value)
+		case "max-image-preview":
+			if imagePreviewRank(value) >= 0 && (rd.MaxImagePreview == "" || imagePreviewRank(value) < imagePreviewRank(rd.MaxImagePreview)) {
+				rd.MaxImagePreview = value
+			}
+		}
+	}
+}
+
+// minLimit returns the more restrictive of the limit cur and the limit
+// written as value, where -1 means unlimited. Unparsable values are ignored.
+func minLimit(cur int, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < -1 {
+		return cur
+	}
+	if cur == -1 || (n != -1 && n < cur) {
+		return n
+	}
+	return cur
+}
+
+// imagePreviewRank orders max-image-preview values from most to least
+// restrictive, returning -1 for unknown values.
+func imagePreviewRank(value string) int {
+	switch value {
+	case "none":
+		return 0
+	case "standard":
+		return 1
+	case "large":
+		return 2
+	}
+	return -1
+}
@@ -0,0 +1,278 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsMaxSize is how much of a robots.txt is read; RFC 9309 requires
+// crawlers to parse at least 500 KiB and lets them ignore the rest.
+const robotsMaxSize = 500 << 10
+
+// robotsRetryDelay is how long a robots.txt that could not be fetched, and so
+// disallows everything, is kept before it is fetched again.
+const robotsRetryDelay = time.Minute
+
+// WithRobotsPolicy makes the Extractor honor robots.txt. When enabled, the
+// robots.txt of each host is fetched before its first page, kept for as
+// many hosts as WithHostCacheSize allows, and requests for disallowed paths,
+// including redirect targets, fail with ErrDisallowedByRobots without being
+// sent.
+//
+// userAgent is the product token matched against User-agent lines, such as
+// "mybot"; if empty, the token of the configured User-Agent header is used.
+// User-agent lines are matched against it case-insensitively, as RFC 9309
+// requires. Following the RFC, a robots.txt answering 4xx allows everything,
+// while a 5xx or unreachable one disallows everything; it is fetched again a
+// minute later, so that a brief outage does not block the host for good.
+func WithRobotsPolicy(enabled bool, userAgent string) Option {
+	return func(e *Extractor) {
+		if !enabled {
+			e.robots = nil
+			return
+		}
+		e.robots = &robotsPolicy{agent: userAgent}
+	}
+}
+
+// robotsPolicy caches the robots.txt rules of each host. New sets hosts.
+type robotsPolicy struct {
+	agent string
+	hosts *hostCache[*robotsEntry]
+}
+
+// robotsEntry holds the rules of one host once fetched. Rules marking
+// robots.txt unavailable are kept until retryAt.
+type robotsEntry struct {
+	mu      sync.Mutex
+	done    bool
+	retryAt time.Time
+	rules   robotsRules
+}
+
+// checkRobots returns an error wrapping ErrDisallowedByRobots if u may not be
+// fetched under the robots policy.
+func (e *Extractor) checkRobots(ctx context.Context, u *url.URL) error {
+	if e.robots == nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Path == "/robots.txt" || ctx.Value(robotsFetchKey{}) != nil {
+		return nil
+	}
+	rules, err := e.robotsRules(ctx, u)
+	if err != nil {
+		return err
+	}
+	if !rules.allowed(u.RequestURI()) {
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, u)
+	}
+	return nil
+}
+
+// checkRobotsURL is checkRobots for a URL already validated.
+func (e *Extractor) checkRobotsURL(ctx context.Context, rawURL string) error {
+	if e.robots == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	return e.checkRobots(ctx, u)
+}
+
+// robotsRules returns the rules applying to u's host, fetching them if needed.
+func (e *Extractor) robotsRules(ctx context.Context, u *url.URL) (robotsRules, error) {
+	origin := strings.ToLower(u.Scheme + "://" + u.Host)
+	entry := e.robots.hosts.get(origin, func() *robotsEntry { return &robotsEntry{} })
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done && (!entry.rules.unavailable || time.Now().Before(entry.retryAt)) {
+		return entry.rules, nil
+	}
+	rules, err := e.fetchRobotsRules(ctx, origin+"/robots.txt")
+	if err != nil {
+		// Only the caller's context failed; try again next time
+		return robotsRules{}, err
+	}
+	entry.rules, entry.done = rules, true
+	if rules.unavailable {
+		entry.retryAt = time.Now().Add(robotsRetryDelay)
+	}
+	return rules, nil
+}
+
+// robotsFetchKey marks the context of a robots.txt fetch, whose redirects
+// are exempt from the policy.
+type robotsFetchKey struct{}
+
+// fetchRobotsRules fetches and parses the robots.txt at robotsURL. The error
+// is only set if ctx ended; other failures are mapped to rules.
+func (e *Extractor) fetchRobotsRules(ctx context.Context, robotsURL string) (robotsRules, error) {
+	req, err := e.newRequest(context.WithValue(ctx, robotsFetchKey{}, true), http.MethodGet, robotsURL)
+	if err != nil {
+		return robotsRules{disallowAll: true}, nil
+	}
+	resp, err := e.do(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return robotsRules{}, err
+		}
+		return robotsUnavailable, nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+	case resp.StatusCode >= 400 && resp.StatusCode <= 499:
+		return robotsRules{}, nil
+	default:
+		return robotsUnavailable, nil
+	}
+	body, err := decodeBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return robotsUnavailable, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(body, robotsMaxSize))
+	if err != nil && ctx.Err() != nil {
+		return robotsRules{}, ctx.Err()
+	}
+	return parseRobotsTxt(data, e.robotsAgent()), nil
+}
+
+// robotsAgent returns the product token matched against User-agent lines.
+func (e *Extractor) robotsAgent() string {
+	agent := e.robots.agent
+	if agent == "" {
+		agent = e.userAgent
+		if ua := e.header.Get("User-Agent"); ua != "" {
+			agent = ua
+		}
+	}
+	return productToken(agent)
+}
+
+// productToken returns the product token of a User-Agent or User-agent line
+// value, such as "mybot" for "MyBot/1.0 (+https://example.com/bot)",
+// lowercased.
+func productToken(agent string) string {
+	agent, _, _ = strings.Cut(agent, "/")
+	if fields := strings.Fields(agent); len(fields) > 0 {
+		agent = fields[0]
+	}
+	return strings.ToLower(agent)
+}
+
+// robotsRule is one Allow or Disallow line.
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsRules are the rules of the group applying to one user agent.
+// unavailable marks the disallowAll of a robots.txt that could not be
+// fetched.
+type robotsRules struct {
+	rules       []robotsRule
+	disallowAll bool
+	unavailable bool
+}
+
+// robotsUnavailable are the rules of a host whose robots.txt could not be
+// fetched.
+var robotsUnavailable = robotsRules{disallowAll: true, unavailable: true}
+
+// allowed reports whether path, including any query, may be fetched. The
+// longest matching rule wins, and Allow wins a tie.
+func (r robotsRules) allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			best, allow = n, rule.allow
+		}
+	}
+	return allow
+}
+
+// parseRobotsTxt returns the rules of data that apply to agent: those of the
+// groups naming it, or else of the "*" groups.
+func parseRobotsTxt(data []byte, agent string) robotsRules {
+	var specific, wildcard []robotsRule
+	var matchesAgent, matchesStar, inRules, sawAgent bool
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field, value = strings.ToLower(strings.TrimSpace(field)), strings.TrimSpace(value)
+		switch field {
+		case "user-agent":
+			if inRules {
+				// A user-agent line after rules starts a new group
+				matchesAgent, matchesStar, inRules = false, false, false
+			}
+			switch name := productToken(value); {
+			case name == "*":
+				matchesStar = true
+			case name != "" && name == agent:
+				matchesAgent = true
+			}
+		case "allow", "disallow":
+			inRules = true
+			sawAgent = sawAgent || matchesAgent
+			if value == "" {
+				// An empty Disallow allows everything, which is the default
+				continue
+			}
+			rule := robotsRule{allow: field == "allow", pattern: value}
+			if matchesAgent {
+				specific = append(specific, rule)
+			}
+			if matchesStar {
+				wildcard = append(wildcard, rule)
+			}
+		}
+	}
+	if sawAgent {
+		return robotsRules{rules: specific}
+	}
+	return robotsRules{rules: wildcard}
+}
+
+// robotsMatch reports whether path matches a robots.txt pattern, in which
+// "*" matches any sequence and a trailing "$" anchors the end.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		j := strings.Index(rest, part)
+		if j < 0 {
+			return false
+		}
+		rest = rest[j+len(part):]
+	}
+	return !anchored || rest == ""
+}
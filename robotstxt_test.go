@@ -0,0 +1,65 @@
+package htmlmetadata
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRobotsTxtAgentMatching(t *testing.T) {
+	tests := []struct {
+		name, robots, agent, path string
+		allowed                   bool
+	}{
+		{"exact token", "User-agent: mybot\nDisallow: /", "mybot", "/", false},
+		{"case-insensitive", "User-agent: MyBot\nDisallow: /", "mybot", "/", false},
+		{"token with version", "User-agent: MyBot/1.0\nDisallow: /", "mybot", "/", false},
+		{"substring of agent", "User-agent: bot\nDisallow: /", "mybot", "/", true},
+		{"empty line matches nobody", "User-agent:\nDisallow: /", "mybot", "/", true},
+		{"empty line before wildcard", "User-agent:\nUser-agent: *\nDisallow: /private", "mybot", "/private", false},
+		{"specific group wins", "User-agent: *\nDisallow: /\n\nUser-agent: mybot\nAllow: /", "mybot", "/", true},
+		{"other agent's group", "User-agent: otherbot\nDisallow: /", "mybot", "/", true},
+	}
+	for _, tt := range tests {
+		rules := parseRobotsTxt([]byte(tt.robots), tt.agent)
+		if got := rules.allowed(tt.path); got != tt.allowed {
+			t.Errorf("%s: allowed(%q) = %v, want %v", tt.name, tt.path, got, tt.allowed)
+		}
+	}
+}
+
+func TestRobotsOutageIsRetried(t *testing.T) {
+	var robotsStatus atomic.Int32
+	robotsStatus.Store(http.StatusServiceUnavailable)
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/robots.txt" {
+			resp := htmlResponse(req, "text/plain", "User-agent: *\nAllow: /\n")
+			resp.StatusCode = int(robotsStatus.Load())
+			return resp, nil
+		}
+		return htmlResponse(req, "text/html", "<title>Page</title>"), nil
+	})
+	e := New(WithTransport(transport), WithRobotsPolicy(true, "mybot"))
+	const page = "https://example.com/page"
+
+	if _, err := e.ExtractResponse(page); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Fatalf("during the outage: err = %v, want ErrDisallowedByRobots", err)
+	}
+	robotsStatus.Store(http.StatusOK)
+	if _, err := e.ExtractResponse(page); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Fatalf("before the retry delay: err = %v, want ErrDisallowedByRobots", err)
+	}
+
+	// Move past the retry delay
+	u, _ := url.Parse(page)
+	entry := e.robots.hosts.get("https://"+u.Host, nil)
+	entry.mu.Lock()
+	entry.retryAt = time.Now().Add(-time.Second)
+	entry.mu.Unlock()
+	if _, err := e.ExtractResponse(page); err != nil {
+		t.Fatalf("after the outage: %v", err)
+	}
+}
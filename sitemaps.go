@@ -0,0 +1,83 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Sitemaps returns the hrefs of rel="sitemap" links, in document order.
+// Sitemaps are more commonly listed in robots.txt; see
+// Extractor.FetchRobotsSitemaps.
+func (r *Result) Sitemaps() []string {
+	var sitemaps []string
+	for _, link := range r.Links {
+		if link.HasRel("sitemap") && link.Href != "" {
+			sitemaps = append(sitemaps, link.Href)
+		}
+	}
+	return sitemaps
+}
+
+// FetchRobotsSitemaps fetches the robots.txt of the site serving pageURL and
+// returns the URLs of its Sitemap lines, in order. A site without robots.txt,
+// answering 404 or 410, has no sitemaps and yields a nil error. The request
+// uses the Extractor's headers, timeout, retries and body size limit.
+func (e *Extractor) FetchRobotsSitemaps(ctx context.Context, pageURL string) ([]string, error) {
+	if err := e.validateURL(pageURL); err != nil {
+		return nil, err
+	}
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	robots := page.ResolveReference(&url.URL{Path: "/robots.txt"})
+
+	req, err := e.newRequest(ctx, http.MethodGet, robots.String())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, nil
+	default:
+		return nil, &StatusError{Code: resp.StatusCode, URL: robots.String()}
+	}
+	body, err := decodeBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	sitemaps, err := parseRobotsSitemaps(bufio.NewScanner(limitBody(body, e.maxBodySize)), robots)
+	if err != nil {
+		return nil, wrapTimeout(fmt.Errorf("failed to read robots.txt: %w", err))
+	}
+	return sitemaps, nil
+}
+
+// parseRobotsSitemaps collects the Sitemap lines of a robots.txt. The field
+// name is case-insensitive and, as it is independent of user-agent groups,
+// may appear anywhere. Relative values are resolved against base.
+func parseRobotsSitemaps(sc *bufio.Scanner, base *url.URL) ([]string, error) {
+	var sitemaps []string
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(field), "sitemap") {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			sitemaps = append(sitemaps, resolveURL(base, value))
+		}
+	}
+	return sitemaps, sc.Err()
+}
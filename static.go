@@ -0,0 +1,65 @@
+package htmlmetadata
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NewStaticTransport returns a RoundTripper that serves canned HTML without
+// touching the network, for testing code that uses an Extractor:
+//
+//	e := htmlmetadata.New(htmlmetadata.WithTransport(htmlmetadata.NewStaticTransport(map[string]string{
+//		"https://example.com/": `<meta property="og:title" content="Example">`,
+//	})))
+//
+// pages maps absolute URLs to documents, served with status 200 and
+// Content-Type text/html; charset=utf-8. A request for any other URL, such as
+// a robots.txt not listed, gets an empty 404. URLs are compared after
+// parsing, so "https://example.com" and "https://example.com/" are distinct,
+// as they are to a server. pages is copied; later changes to it have no
+// effect.
+func NewStaticTransport(pages map[string]string) http.RoundTripper {
+	t := staticTransport{pages: make(map[string]string, len(pages))}
+	for rawURL, body := range pages {
+		if u, err := url.Parse(rawURL); err == nil {
+			rawURL = u.String()
+		}
+		t.pages[rawURL] = body
+	}
+	return t
+}
+
+// staticTransport is the RoundTripper of NewStaticTransport.
+type staticTransport struct {
+	pages map[string]string
+}
+
+func (t staticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	body, ok := t.pages[req.URL.String()]
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Request:    req,
+	}
+	if !ok {
+		resp.Status, resp.StatusCode = "404 Not Found", http.StatusNotFound
+		resp.Header = http.Header{}
+	}
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	resp.ContentLength = int64(len(body))
+	if req.Method == http.MethodHead {
+		body = ""
+	}
+	resp.Body = io.NopCloser(strings.NewReader(body))
+	return resp, nil
+}
@@ -0,0 +1,120 @@
+package htmlmetadata
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithTLSConfig sets the TLS configuration used for HTTPS requests, for
+// example to trust an internal certificate authority through RootCAs or to
+// present a client certificate. The configuration is copied; later changes
+// to config have no effect.
+//
+// Like WithProxy, it is applied to a copy of the transport from
+// WithTransport, which must then be an *http.Transport, or of
+// http.DefaultTransport.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(e *Extractor) {
+		e.tlsConfig = config
+	}
+}
+
+// WithInsecureSkipVerify disables verification of server certificates when
+// skip is true.
+//
+// This is dangerous: any machine on the network path can then impersonate
+// the server and read or alter the traffic. It is meant for crawling
+// internal hosts with self-signed certificates; prefer adding their
+// certificate authority with WithTLSConfig, and never enable this for
+// crawls of the public web.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(e *Extractor) {
+		e.insecureSkipVerify = skip
+	}
+}
+
+// WithMaxConnsPerHost caps the connections open to any one host at n, idle
+// ones included, so strict servers are not flooded with parallel
+// connections. Requests beyond the cap wait for a connection to free up.
+// Over HTTP/2, requests to a host share a connection, so the cap limits
+// connections rather than requests in flight.
+//
+// For polite crawling, 2 to 6 connections per host is usual, browsers use 6
+// for HTTP/1.1; combine it with WithRateLimit to also bound the request
+// rate. The cap only applies to the transport the Extractor builds itself:
+// with WithTransport, the connection settings of the transport given win
+// and n is ignored.
+func WithMaxConnsPerHost(n int) Option {
+	return func(e *Extractor) {
+		e.maxConnsPerHost = n
+	}
+}
+
+// WithResolver looks up host names with resolver instead of the system's
+// resolver, for example one whose Dial sends queries to a specific server,
+// or through DNS over HTTPS, so lookups do not leak to the local network.
+//
+// Like WithMaxConnsPerHost, it only applies to the transport the Extractor
+// builds itself: with WithTransport or WithHTTPClient, configure the dialer
+// of the transport given instead. Behind a proxy from WithProxy, requests
+// are sent with their host name and the proxy resolves it; resolver is then
+// only used to find a SOCKS5 proxy named by host. .i2p hosts configured with
+// WithI2P keep using their own transport.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(e *Extractor) {
+		e.resolver = resolver
+	}
+}
+
+// configureTransport returns a copy of base with the proxy and TLS options
+// applied, and, if own reports that base is the default transport rather
+// than one from WithTransport, the connection limits and resolver.
+func (e *Extractor) configureTransport(base http.RoundTripper, own bool) http.RoundTripper {
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return errTransport{fmt.Errorf("transport %T is not an *http.Transport, as WithProxy and WithTLSConfig require", base)}
+	}
+	t = t.Clone()
+	if own && e.maxConnsPerHost > 0 {
+		t.MaxConnsPerHost = e.maxConnsPerHost
+		t.MaxIdleConnsPerHost = e.maxConnsPerHost
+	}
+	// The timeouts of http.DefaultTransport's dialer, which also reaches a
+	// SOCKS5 proxy
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if own && e.resolver != nil {
+		dialer.Resolver = e.resolver
+		t.DialContext = dialer.DialContext
+	}
+	if e.tlsConfig != nil {
+		t.TLSClientConfig = e.tlsConfig.Clone()
+	}
+	if e.insecureSkipVerify {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if e.proxyURL != "" {
+		if err := setProxy(t, e.proxyURL, dialer); err != nil {
+			return errTransport{err}
+		}
+	}
+	return t
+}
+
+// errTransport fails every request with err, reporting a configuration
+// problem that options have no way to return.
+type errTransport struct {
+	err error
+}
+
+func (t errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
@@ -0,0 +1,57 @@
+package htmlmetadata
+
+import "strings"
+
+// TwitterCard holds the twitter:* properties of a page.
+type TwitterCard struct {
+	Card        string `json:"card,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Image is twitter:image, or the legacy twitter:image:src.
+	Image    string `json:"image,omitempty"`
+	ImageAlt string `json:"image_alt,omitempty"`
+	// Site and Creator are the @usernames of the site and the content author.
+	Site    string `json:"site,omitempty"`
+	Creator string `json:"creator,omitempty"`
+	// Extra holds twitter:* properties without a dedicated field, keyed by the
+	// full name. If a name repeats, the first value is kept.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// ParseTwitterCard collects the twitter:* entries from tags into a
+// TwitterCard. The specification places them in the name attribute, but
+// property is accepted too since many pages use it; http-equiv tags are
+// ignored.
+//
+// Only twitter:* tags are read. Pages often declare just twitter:card and rely
+// on OpenGraph for the rest; merging the two is left to the caller.
+func ParseTwitterCard(tags []MetaTag) TwitterCard {
+	tc := TwitterCard{Extra: make(map[string]string)}
+	for _, tag := range tags {
+		tag.Name = vocabularyName(tag, "twitter:")
+		if tag.Source == SourceHTTPEquiv || !strings.HasPrefix(tag.Name, "twitter:") {
+			continue
+		}
+		switch tag.Name {
+		case "twitter:card":
+			setFirst(&tc.Card, tag.Content)
+		case "twitter:title":
+			setFirst(&tc.Title, tag.Content)
+		case "twitter:description":
+			setFirst(&tc.Description, tag.Content)
+		case "twitter:image", "twitter:image:src":
+			setFirst(&tc.Image, tag.Content)
+		case "twitter:image:alt":
+			setFirst(&tc.ImageAlt, tag.Content)
+		case "twitter:site":
+			setFirst(&tc.Site, tag.Content)
+		case "twitter:creator":
+			setFirst(&tc.Creator, tag.Content)
+		default:
+			if _, ok := tc.Extra[tag.Name]; !ok {
+				tc.Extra[tag.Name] = tag.Content
+			}
+		}
+	}
+	return tc
+}
@@ -0,0 +1,175 @@
+package htmlmetadata
+
+import (
+	"net/url"
+	"strings"
+)
+
+// urlValuedNames lists the meta names and properties whose content is a URL
+// and is therefore resolved against the document base.
+var urlValuedNames = map[string]bool{
+	"og:url":                  true,
+	"og:image":                true,
+	"og:image:url":            true,
+	"og:image:secure_url":     true,
+	"og:video":                true,
+	"og:video:url":            true,
+	"og:video:secure_url":     true,
+	"og:audio":                true,
+	"og:audio:url":            true,
+	"og:audio:secure_url":     true,
+	"twitter:image":           true,
+	"twitter:image:src":       true,
+	"twitter:player":          true,
+	"msapplication-tileimage": true,
+}
+
+// isURLValued reports whether the content of a meta tag called name is a URL.
+func isURLValued(name string) bool {
+	return urlValuedNames[strings.ToLower(name)]
+}
+
+// ResolveReference resolves ref against the document base: the <base href>
+// if present, otherwise the URL the document was fetched from. ref is returned
+// unchanged when no absolute base is known.
+func (r *Result) ResolveReference(ref string) string {
+	return resolveURL(r.base, ref)
+}
+
+// Canonical returns the preferred absolute URL of the document: the first
+// rel="canonical" link, else the first og:url, else URL. Candidates that are
+// empty, unparsable, not absolute http or https URLs after resolution, or,
+// with WithStrictCanonical, on a host other than URL's, are skipped. It
+// returns "" if no candidate qualifies.
+func (r *Result) Canonical() string {
+	var candidates []string
+	for _, link := range r.Links {
+		if link.HasRel("canonical") {
+			candidates = append(candidates, link.Href)
+			break
+		}
+	}
+	for _, tag := range r.Tags {
+		if strings.EqualFold(tag.Name, "og:url") {
+			candidates = append(candidates, tag.Content)
+			break
+		}
+	}
+	candidates = append(candidates, r.URL)
+
+	for _, c := range candidates {
+		u, err := url.Parse(strings.TrimSpace(r.ResolveReference(c)))
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if r.strictCanonical && !sameSite(u, r.URL) {
+			continue
+		}
+		return u.String()
+	}
+	return ""
+}
+
+// sameSite reports whether u is on the host of pageURL, ignoring case and a
+// leading "www.". It returns true when pageURL has no host to compare with.
+func sameSite(u *url.URL, pageURL string) bool {
+	page, err := url.Parse(pageURL)
+	if err != nil || page.Host == "" {
+		return true
+	}
+	trim := func(host string) string {
+		host = strings.ToLower(host)
+		return strings.TrimPrefix(host, "www.")
+	}
+	return trim(u.Hostname()) == trim(page.Hostname())
+}
+
+// documentBase returns the URL relative references in a document resolve
+// against: the <base href> resolved against the page URL, or the page URL
+// alone. It returns nil when neither is an absolute URL.
+func documentBase(pageURL, baseHref string) *url.URL {
+	page, err := url.Parse(pageURL)
+	if err != nil || !page.IsAbs() {
+		page = nil
+	}
+	if baseHref != "" {
+		if href, err := url.Parse(baseHref); err == nil {
+			if page != nil {
+				return page.ResolveReference(href)
+			}
+			if href.IsAbs() {
+				return href
+			}
+		}
+	}
+	return page
+}
+
+// resolveURL resolves ref against base. ref is returned unchanged if it is
+// empty, unparsable, or there is no base to resolve against.
+func resolveURL(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// DefaultTrackingParams are the query parameters WithCanonicalizeURLs strips
+// when given no list of its own. A trailing "*" matches any suffix.
+var DefaultTrackingParams = []string{"utm_*", "fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid", "_ga"}
+
+// canonicalizeURL normalizes an absolute URL: the scheme and host are
+// lowercased, the scheme's default port is dropped and query parameters
+// matching strip are removed, keeping the order of the rest. Relative or
+// unparsable URLs are returned unchanged.
+func canonicalizeURL(raw string, strip []string) string {
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	if u.RawQuery != "" {
+		var kept []string
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			key, _, _ := strings.Cut(pair, "=")
+			if name, err := url.QueryUnescape(key); err == nil && matchesParam(name, strip) {
+				continue
+			}
+			kept = append(kept, pair)
+		}
+		u.RawQuery = strings.Join(kept, "&")
+		u.ForceQuery = false
+	}
+	return u.String()
+}
+
+// matchesParam reports whether the query parameter name matches one of
+// patterns, case-insensitively.
+func matchesParam(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(name, p) {
+			return true
+		}
+	}
+	return false
+}
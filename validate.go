@@ -0,0 +1,150 @@
+package htmlmetadata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity ranks an Issue reported by Validate.
+type Severity int
+
+const (
+	// SeverityInfo marks an omission worth knowing about that rarely matters.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks tags that are missing or sized so that search
+	// results and link previews suffer.
+	SeverityWarning
+	// SeverityError marks omissions that leave search engines and previews
+	// without the basics.
+	SeverityError
+)
+
+// String returns the lowercase name of s.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Rules checked by Validate, as reported in Issue.Rule and listed in
+// Ruleset.Disabled.
+const (
+	// RuleMissingTitle (error): the page has no non-blank <title>.
+	RuleMissingTitle = "missing-title"
+	// RuleTitleTooLong (warning): the <title> is longer than
+	// Ruleset.MaxTitleLength characters, so search results cut it short.
+	RuleTitleTooLong = "title-too-long"
+	// RuleMissingDescription (warning): there is no non-blank
+	// name="description" tag.
+	RuleMissingDescription = "missing-description"
+	// RuleDescriptionTooShort (warning): the description is shorter than
+	// Ruleset.MinDescriptionLength characters.
+	RuleDescriptionTooShort = "description-too-short"
+	// RuleDescriptionTooLong (warning): the description is longer than
+	// Ruleset.MaxDescriptionLength characters.
+	RuleDescriptionTooLong = "description-too-long"
+	// RuleMissingOpenGraphImage (warning): there is no non-blank og:image,
+	// so link previews show no picture.
+	RuleMissingOpenGraphImage = "missing-og-image"
+	// RuleMissingCanonical (warning): there is no rel="canonical" link.
+	// og:url and the fetched URL, which Canonical falls back to, do not count.
+	RuleMissingCanonical = "missing-canonical"
+	// RuleMissingViewport (warning): there is no name="viewport" tag, so
+	// mobile browsers render the page zoomed out.
+	RuleMissingViewport = "missing-viewport"
+	// RuleMissingLang (info): the <html> element has no lang attribute.
+	RuleMissingLang = "missing-lang"
+)
+
+// Ruleset holds the thresholds Validate applies. A threshold of zero or less
+// turns its rule off.
+type Ruleset struct {
+	// MaxTitleLength is the longest <title>, in characters, before
+	// RuleTitleTooLong is reported.
+	MaxTitleLength int
+	// MinDescriptionLength and MaxDescriptionLength bound the description,
+	// in characters, for RuleDescriptionTooShort and RuleDescriptionTooLong.
+	MinDescriptionLength int
+	MaxDescriptionLength int
+	// Disabled lists rules, such as RuleMissingLang, that are not checked.
+	Disabled []string
+}
+
+// DefaultRuleset is the Ruleset of Result.Validate, with the limits search
+// engines commonly display: titles up to 60 characters, descriptions of 50
+// to 160.
+var DefaultRuleset = Ruleset{
+	MaxTitleLength:       60,
+	MinDescriptionLength: 50,
+	MaxDescriptionLength: 160,
+}
+
+// Issue is a finding of Validate.
+type Issue struct {
+	// Rule is the rule that found it, such as RuleMissingTitle.
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Message describes the issue for people, and may change between
+	// versions; match on Rule instead.
+	Message string `json:"message"`
+}
+
+// Validate checks r against DefaultRuleset, reporting missing and badly
+// sized recommended tags.
+func (r *Result) Validate() []Issue {
+	return DefaultRuleset.Validate(r)
+}
+
+// Validate checks r against the rules of rs, reporting issues in the order
+// the rules are declared. Lengths are counted in characters after trimming
+// surrounding whitespace. It returns nil if r passes every rule.
+func (rs Ruleset) Validate(r *Result) []Issue {
+	var issues []Issue
+	report := func(rule string, severity Severity, format string, args ...interface{}) {
+		if !slices.Contains(rs.Disabled, rule) {
+			issues = append(issues, Issue{Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		report(RuleMissingTitle, SeverityError, "page has no title")
+	} else if n := utf8.RuneCountInString(title); rs.MaxTitleLength > 0 && n > rs.MaxTitleLength {
+		report(RuleTitleTooLong, SeverityWarning, "title is %d characters, more than %d", n, rs.MaxTitleLength)
+	}
+
+	description, _ := r.Get("description")
+	description = strings.TrimSpace(description)
+	n := utf8.RuneCountInString(description)
+	switch {
+	case description == "":
+		report(RuleMissingDescription, SeverityWarning, "page has no description")
+	case rs.MinDescriptionLength > 0 && n < rs.MinDescriptionLength:
+		report(RuleDescriptionTooShort, SeverityWarning, "description is %d characters, fewer than %d", n, rs.MinDescriptionLength)
+	case rs.MaxDescriptionLength > 0 && n > rs.MaxDescriptionLength:
+		report(RuleDescriptionTooLong, SeverityWarning, "description is %d characters, more than %d", n, rs.MaxDescriptionLength)
+	}
+
+	if image, _ := r.Get("og:image"); strings.TrimSpace(image) == "" {
+		report(RuleMissingOpenGraphImage, SeverityWarning, "page has no og:image")
+	}
+	if !slices.ContainsFunc(r.Links, func(link LinkTag) bool { return link.HasRel("canonical") && strings.TrimSpace(link.Href) != "" }) {
+		report(RuleMissingCanonical, SeverityWarning, "page has no canonical link")
+	}
+	if _, ok := r.Viewport(); !ok {
+		report(RuleMissingViewport, SeverityWarning, "page has no viewport meta tag")
+	}
+	if strings.TrimSpace(r.Lang) == "" {
+		report(RuleMissingLang, SeverityInfo, "html element has no lang attribute")
+	}
+	return issues
+}
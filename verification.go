@@ -0,0 +1,47 @@
+package htmlmetadata
+
+import "strings"
+
+// DefaultVerificationNames are the meta names Verifications recognizes as
+// site ownership tokens.
+var DefaultVerificationNames = []string{
+	"google-site-verification",
+	"msvalidate.01",
+	"yandex-verification",
+	"baidu-site-verification",
+	"p:domain_verify",
+	"facebook-domain-verification",
+	"norton-safeweb-site-verification",
+	"ahrefs-site-verification",
+	"apple-domain-verification",
+}
+
+// Verifications returns the site verification tokens in tags, keyed by
+// lowercase meta name, recognizing DefaultVerificationNames.
+func Verifications(tags []MetaTag) map[string]string {
+	return VerificationsNamed(tags, DefaultVerificationNames)
+}
+
+// VerificationsNamed returns the tokens of the name= tags in tags whose name
+// is one of names, compared case-insensitively, keyed by lowercase name. When
+// a name repeats, as it may for a site verified by several accounts, the
+// first token is kept.
+func VerificationsNamed(tags []MetaTag, names []string) map[string]string {
+	tokens := make(map[string]string)
+	for _, tag := range tags {
+		if tag.Source != SourceName {
+			continue
+		}
+		key := strings.ToLower(tag.Name)
+		if _, ok := tokens[key]; ok {
+			continue
+		}
+		for _, name := range names {
+			if strings.EqualFold(key, name) {
+				tokens[key] = strings.TrimSpace(tag.Content)
+				break
+			}
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,26 @@
+package htmlmetadata
+
+import "golang.org/x/net/html"
+
+// TagVisitor receives elements as a document is traversed, letting callers
+// extract data the package does not collect itself. The built-in meta, link
+// and title extraction is itself a TagVisitor that runs before any
+// registered with WithVisitor.
+//
+// Visit is called for every element node in document order, including those
+// inside <noscript>, whose content is parsed separately and so is not
+// attached to the rest of the tree. ParseHead, which
+// does not build a DOM, only visits elements in <head> and passes detached
+// nodes: they have no parent or siblings, and only <title> and <script> have
+// their text as a child.
+type TagVisitor interface {
+	Visit(n *html.Node)
+}
+
+// TagVisitorFunc adapts an ordinary function to a TagVisitor.
+type TagVisitorFunc func(n *html.Node)
+
+// Visit calls f(n).
+func (f TagVisitorFunc) Visit(n *html.Node) {
+	f(n)
+}
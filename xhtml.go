@@ -0,0 +1,142 @@
+package htmlmetadata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Namespaces of elements and attributes in XHTML documents.
+const (
+	xhtmlNamespace  = "http://www.w3.org/1999/xhtml"
+	svgNamespace    = "http://www.w3.org/2000/svg"
+	mathMLNamespace = "http://www.w3.org/1998/Math/MathML"
+	xmlNamespace    = "http://www.w3.org/XML/1998/namespace"
+)
+
+// xhtmlReader reports whether the document in r is XHTML: it is served as
+// application/xhtml+xml, or, when there is no Content-Type to go by, starts
+// with an XML declaration or an XHTML doctype. Browsers parse XHTML doctypes
+// served as text/html as HTML, and so does the Extractor.
+//
+// r must already be decoded to UTF-8. The returned reader replaces it.
+func xhtmlReader(r io.Reader, contentType string) (io.Reader, bool) {
+	if contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		return r, mediaType == "application/xhtml+xml"
+	}
+	br := bufio.NewReaderSize(r, sniffLen)
+	preview, _ := br.Peek(sniffLen)
+	preview = bytes.TrimLeft(preview, asciiSpace)
+	switch {
+	case bytes.HasPrefix(preview, []byte("<?xml")):
+		return br, true
+	case len(preview) >= len("<!doctype") && bytes.EqualFold(preview[:len("<!doctype")], []byte("<!doctype")):
+		end := bytes.IndexByte(preview, '>')
+		return br, end > 0 && bytes.Contains(preview[:end], []byte("//DTD XHTML"))
+	}
+	return br, false
+}
+
+// parseXHTML parses data as XML into the same kind of tree html.Parse
+// returns, so the rest of the package treats both alike. Unlike the HTML
+// parser it honours self-closing syntax such as <script/> and <title/>, and
+// it recognises elements by namespace rather than by prefix: XHTML elements
+// get an empty Namespace, SVG and MathML "svg" and "math", and anything else
+// its namespace URL. xml:lang is reported as lang, which it overrides.
+//
+// HTML entities and void elements written without a closing slash are
+// accepted, but the document must otherwise be well-formed; callers fall back
+// to html.Parse if it is not. If headOnly is set, parsing stops at </head>.
+func parseXHTML(data []byte, headOnly bool) (*html.Node, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	// The document has already been decoded to UTF-8
+	d.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+
+	doc := &html.Node{Type: html.DocumentNode}
+	parent := doc
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := xhtmlElement(t)
+			parent.AppendChild(n)
+			parent = n
+		case xml.EndElement:
+			if parent.Namespace == "" && parent.DataAtom == atom.Head && headOnly {
+				return doc, nil
+			}
+			if parent.Parent != nil {
+				parent = parent.Parent
+			}
+		case xml.CharData:
+			parent.AppendChild(&html.Node{Type: html.TextNode, Data: string(t)})
+		case xml.Comment:
+			parent.AppendChild(&html.Node{Type: html.CommentNode, Data: string(t)})
+		}
+	}
+	if parent != doc {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return doc, nil
+}
+
+// xhtmlElement converts an XML start element to an element node.
+func xhtmlElement(t xml.StartElement) *html.Node {
+	n := &html.Node{Type: html.ElementNode, Data: t.Name.Local}
+	switch t.Name.Space {
+	case "", xhtmlNamespace:
+		n.DataAtom = atom.Lookup([]byte(t.Name.Local))
+	case svgNamespace:
+		n.Namespace = "svg"
+	case mathMLNamespace:
+		n.Namespace = "math"
+	default:
+		n.Namespace = t.Name.Space
+	}
+
+	xmlLang, hasXMLLang := "", false
+	for _, a := range t.Attr {
+		switch {
+		case a.Name.Space == "xmlns":
+			n.Attr = append(n.Attr, html.Attribute{Key: "xmlns:" + a.Name.Local, Val: a.Value})
+		case a.Name.Space == xmlNamespace || a.Name.Space == "xml":
+			if a.Name.Local == "lang" {
+				xmlLang, hasXMLLang = a.Value, true
+			}
+		case a.Name.Space == "":
+			n.Attr = append(n.Attr, html.Attribute{Key: a.Name.Local, Val: a.Value})
+		default:
+			n.Attr = append(n.Attr, html.Attribute{Namespace: a.Name.Space, Key: a.Name.Local, Val: a.Value})
+		}
+	}
+	if hasXMLLang {
+		n.Attr = setAttr(n.Attr, "lang", xmlLang)
+	}
+	return n
+}
+
+// setAttr sets the attribute key in attrs, replacing any existing value.
+func setAttr(attrs []html.Attribute, key, val string) []html.Attribute {
+	for i, a := range attrs {
+		if a.Namespace == "" && a.Key == key {
+			attrs[i].Val = val
+			return attrs
+		}
+	}
+	return append(attrs, html.Attribute{Key: key, Val: val})
+}
@@ -0,0 +1,85 @@
+package htmlmetadata
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// extractXHTML serves doc as application/xhtml+xml and extracts it.
+func extractXHTML(t *testing.T, doc string) *Result {
+	t.Helper()
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return htmlResponse(req, "application/xhtml+xml", doc), nil
+	})
+	res, err := New(WithTransport(transport)).ExtractResponse("https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractResponse: %v", err)
+	}
+	return res
+}
+
+func TestXHTMLNamespacedMeta(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<h:html xmlns:h="http://www.w3.org/1999/xhtml" xmlns:x="urn:example:other" xml:lang="fr">
+<h:head>
+<h:meta property="og:title" content="Prefixed"/>
+<x:meta name="foreign" content="not html"/>
+<meta xmlns="http://www.w3.org/1999/xhtml" name="description" content="Default namespace"/>
+</h:head>
+<h:body/>
+</h:html>`
+	res := extractXHTML(t, doc)
+	if got, _ := res.Get("og:title"); got != "Prefixed" {
+		t.Errorf("prefixed XHTML meta: og:title = %q", got)
+	}
+	if got, _ := res.Get("description"); got != "Default namespace" {
+		t.Errorf("default namespace meta: description = %q", got)
+	}
+	if _, ok := res.AsMap(FirstWins)["foreign"]; ok {
+		t.Error("a meta element in another namespace was taken for an XHTML one")
+	}
+	if res.Lang != "fr" {
+		t.Errorf("Lang = %q, want xml:lang", res.Lang)
+	}
+}
+
+func TestXHTMLSelfClosingElements(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head>
+<title/>
+<script src="/app.js"/>
+<meta name="first" content="1"/>
+<meta name="second" content="2" />
+</head><body/></html>`
+	res := extractXHTML(t, doc)
+	// The HTML parser would read everything after <title/> or <script/> as
+	// their text
+	if res.Title != "" {
+		t.Errorf("Title = %q, want the empty <title/>", res.Title)
+	}
+	var names []string
+	for _, tag := range res.Tags {
+		names = append(names, tag.Name+"="+tag.Content)
+	}
+	if got := strings.Join(names, " "); got != "first=1 second=2" {
+		t.Errorf("tags after self-closing elements: %q", got)
+	}
+}
+
+func TestXHTMLDoctypeWithoutContentType(t *testing.T) {
+	const doc = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title/><meta name="a" content="b"/></head></html>`
+	for method, parse := range map[string]func(*Extractor) (*Result, error){
+		"Parse":     func(e *Extractor) (*Result, error) { return e.Parse(strings.NewReader(doc)) },
+		"ParseHead": func(e *Extractor) (*Result, error) { return e.ParseHead(strings.NewReader(doc)) },
+	} {
+		res, err := parse(New())
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if got, _ := res.Get("a"); got != "b" || res.Title != "" {
+			t.Errorf("%s: a = %q, Title = %q", method, got, res.Title)
+		}
+	}
+}